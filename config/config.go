@@ -0,0 +1,166 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const DefaultGoogleSheetRange = "Sheet1!A1:B"
+const DefaultGoogleSheetSAPath = "/app/sa.json"
+const DefaultGoogleSheetOAuthCredPath = "/app/oauth-cred.json"
+const DefaultGoogleSheetOAuthTokenPath = "/tmp/oauth-token.json"
+const DefaultFundCachePath = "/tmp/priceupdater-fund-cache.json"
+const DefaultFundCacheTTL = 30 * 24 * time.Hour
+const DefaultGoogleSheetFundInfoTab = "Fund Info"
+const DefaultGoogleSheetDividendTab = "Dividends"
+
+const PriceJob = "price"
+const FundInfoJob = "fund_info"
+const FundDividendJob = "fund_dividend"
+
+type Config struct {
+	SymbolMap string `yaml:"symbol_map"`
+	Jobs      []Job  `yaml:"jobs"`
+}
+
+type Job struct {
+	Name      string           `yaml:"name"`
+	Kind      string           `yaml:"kind"`
+	Schedule  string           `yaml:"schedule"`
+	Jitter    time.Duration    `yaml:"jitter"`
+	Timeout   time.Duration    `yaml:"timeout"`
+	Oracle    Oracle           `yaml:"oracle"`
+	FX        FX               `yaml:"fx"`
+	Precision map[string]int32 `yaml:"precision"`
+	Updater   Updater          `yaml:"updater"`
+}
+
+type FX struct {
+	Currencies []string `yaml:"currencies"`
+}
+
+type Oracle struct {
+	Type                string            `yaml:"type"`
+	Targets             []string          `yaml:"targets"`
+	APIKey              string            `yaml:"api_key"`
+	URL                 string            `yaml:"url"`
+	QuoteCurrencies     []string          `yaml:"quote_currencies"`
+	Currencies          map[string]string `yaml:"currencies"`
+	USDTAsUSD           bool              `yaml:"usdt_as_usd"`
+	FundFactAPIKey      string            `yaml:"fund_fact_api_key"`
+	FundDailyInfoAPIKey string            `yaml:"fund_daily_info_api_key"`
+	NavLookbackDays     int               `yaml:"nav_lookback_days"`
+	Concurrency         int               `yaml:"concurrency"`
+	RequestsPerSecond   float64           `yaml:"requests_per_second"`
+	FundCachePath       string            `yaml:"fund_cache_path"`
+	FundCacheTTL        time.Duration     `yaml:"fund_cache_ttl"`
+	SkipNoNewNav        bool              `yaml:"skip_no_new_nav"`
+	HolidayCalendar     string            `yaml:"holiday_calendar"`
+	Providers           []Oracle          `yaml:"providers"`
+	MaxDeviationPercent float64           `yaml:"max_deviation_percent"`
+}
+
+type Updater struct {
+	Type                string `yaml:"type"`
+	SheetID             string `yaml:"sheet_id"`
+	Range               string `yaml:"range"`
+	ServiceAccountPath  string `yaml:"service_account_path"`
+	OAuthCredentialPath string `yaml:"oauth_credential_path"`
+	OAuthTokenPath      string `yaml:"oauth_token_path"`
+	Merge               bool   `yaml:"merge"`
+	HistoryTab          string `yaml:"history_tab"`
+	MarketColumns       bool   `yaml:"market_columns"`
+	FundInfoTab         string `yaml:"fund_info_tab"`
+	DividendTab         string `yaml:"dividend_tab"`
+	Path                string `yaml:"path"`
+	Append              bool   `yaml:"append"`
+	DSN                 string `yaml:"dsn"`
+}
+
+// Load reads a YAML job file. ${VAR} references are expanded from the
+// environment so secrets don't have to live in the file.
+func Load(path string) (*Config, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("fail to read config file: %w", err)
+	}
+
+	return Parse([]byte(os.ExpandEnv(string(b))))
+}
+
+func Parse(b []byte) (*Config, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(b))
+	decoder.KnownFields(true)
+
+	var config Config
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("fail to parse config file: %w", err)
+	}
+
+	if len(config.Jobs) == 0 {
+		return nil, fmt.Errorf("config file declares no jobs")
+	}
+
+	for i := range config.Jobs {
+		job := &config.Jobs[i]
+		if job.Name == "" {
+			job.Name = fmt.Sprintf("job-%d", i+1)
+		}
+		if job.Kind == "" {
+			job.Kind = PriceJob
+		}
+		if job.Kind != PriceJob && job.Kind != FundInfoJob && job.Kind != FundDividendJob {
+			return nil, fmt.Errorf("job=%s unknown kind %s", job.Name, job.Kind)
+		}
+		if job.Oracle.Type == "" {
+			return nil, fmt.Errorf("job=%s missing oracle type", job.Name)
+		}
+		if job.Updater.Type == "" {
+			return nil, fmt.Errorf("job=%s missing updater type", job.Name)
+		}
+		for key, places := range job.Precision {
+			if places < 0 {
+				return nil, fmt.Errorf("job=%s invalid precision %d for %s", job.Name, places, key)
+			}
+		}
+		job.Oracle.applyDefaults()
+		job.Updater.applyDefaults()
+	}
+
+	return &config, nil
+}
+
+func (oracle *Oracle) applyDefaults() {
+	if oracle.FundCachePath == "" {
+		oracle.FundCachePath = DefaultFundCachePath
+	}
+	if oracle.FundCacheTTL == 0 {
+		oracle.FundCacheTTL = DefaultFundCacheTTL
+	}
+}
+
+func (updater *Updater) applyDefaults() {
+	if updater.Range == "" {
+		updater.Range = DefaultGoogleSheetRange
+	}
+	if updater.FundInfoTab == "" {
+		updater.FundInfoTab = DefaultGoogleSheetFundInfoTab
+	}
+	if updater.DividendTab == "" {
+		updater.DividendTab = DefaultGoogleSheetDividendTab
+	}
+	if updater.ServiceAccountPath == "" {
+		updater.ServiceAccountPath = DefaultGoogleSheetSAPath
+	}
+	if updater.OAuthCredentialPath == "" {
+		updater.OAuthCredentialPath = DefaultGoogleSheetOAuthCredPath
+	}
+	if updater.OAuthTokenPath == "" {
+		updater.OAuthTokenPath = DefaultGoogleSheetOAuthTokenPath
+	}
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	config, err := Parse([]byte(`
+jobs:
+  - name: crypto
+    schedule: "*/5 * * * *"
+    jitter: 30s
+    precision:
+      BTC: 2
+    oracle:
+      type: coingecko
+      targets: [bitcoin, ethereum]
+    updater:
+      type: gsheet-oauth
+      sheet_id: sheet
+      range: Crypto!A1:C
+  - kind: fund_info
+    oracle:
+      type: thaisec
+      targets: [SCBNK225]
+      fund_fact_api_key: key1
+      fund_daily_info_api_key: key2
+    updater:
+      type: csv
+      path: /tmp/funds.csv
+`))
+	if err != nil {
+		t.FailNow()
+	}
+
+	assert.Len(t, config.Jobs, 2)
+	assert.Equal(t, "crypto", config.Jobs[0].Name)
+	assert.Equal(t, "*/5 * * * *", config.Jobs[0].Schedule)
+	assert.Equal(t, 30*time.Second, config.Jobs[0].Jitter)
+	assert.Equal(t, map[string]int32{"BTC": 2}, config.Jobs[0].Precision)
+	assert.Equal(t, []string{"bitcoin", "ethereum"}, config.Jobs[0].Oracle.Targets)
+	assert.Equal(t, "Crypto!A1:C", config.Jobs[0].Updater.Range)
+	assert.Equal(t, DefaultGoogleSheetOAuthTokenPath, config.Jobs[0].Updater.OAuthTokenPath)
+	assert.Equal(t, PriceJob, config.Jobs[0].Kind)
+	assert.Equal(t, "job-2", config.Jobs[1].Name)
+	assert.Equal(t, FundInfoJob, config.Jobs[1].Kind)
+	assert.Equal(t, DefaultGoogleSheetFundInfoTab, config.Jobs[1].Updater.FundInfoTab)
+	assert.Equal(t, DefaultGoogleSheetDividendTab, config.Jobs[1].Updater.DividendTab)
+	assert.Equal(t, "key2", config.Jobs[1].Oracle.FundDailyInfoAPIKey)
+	assert.Equal(t, DefaultFundCachePath, config.Jobs[1].Oracle.FundCachePath)
+	assert.Equal(t, DefaultFundCacheTTL, config.Jobs[1].Oracle.FundCacheTTL)
+	assert.Equal(t, "/tmp/funds.csv", config.Jobs[1].Updater.Path)
+}
+
+func TestParseInvalid(t *testing.T) {
+	_, err := Parse([]byte(`jobs: []`))
+	assert.Error(t, err)
+
+	_, err = Parse([]byte(`
+jobs:
+  - oracle:
+      type: coingecko
+`))
+	assert.Error(t, err)
+
+	_, err = Parse([]byte(`
+jobs:
+  - oracle:
+      type: coingecko
+      unknown_key: true
+    updater:
+      type: csv
+`))
+	assert.Error(t, err)
+
+	_, err = Parse([]byte(`
+jobs:
+  - precision:
+      BTC: -1
+    oracle:
+      type: coingecko
+    updater:
+      type: csv
+`))
+	assert.Error(t, err)
+
+	_, err = Parse([]byte(`
+jobs:
+  - kind: unknown
+    oracle:
+      type: thaisec
+    updater:
+      type: csv
+`))
+	assert.Error(t, err)
+}
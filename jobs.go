@@ -0,0 +1,412 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/koromo-wd/priceupdater/config"
+	"github.com/koromo-wd/priceupdater/oracle"
+	"github.com/koromo-wd/priceupdater/updater"
+)
+
+type jobRunner struct {
+	job      config.Job
+	oracle   oracle.Oracle
+	fxOracle oracle.FXOracle
+	updater  updater.Updater
+}
+
+func runJob(ctx context.Context, job config.Job, registry *oracle.SymbolRegistry) error {
+	runner, err := newJobRunner(job, registry)
+	if err != nil {
+		return err
+	}
+	return runner.run(ctx)
+}
+
+func newJobRunner(job config.Job, registry *oracle.SymbolRegistry) (*jobRunner, error) {
+	var quoteOracle oracle.Oracle
+	var err error
+	if job.Kind == config.FundInfoJob || job.Kind == config.FundDividendJob {
+		quoteOracle, err = newBaseOracle(job.Oracle)
+	} else {
+		quoteOracle, err = newOracle(job.Oracle, registry)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("couldn't initialize oracle: %w", err)
+	}
+
+	priceUpdater, err := newUpdater(job.Updater)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't initialize updater: %w", err)
+	}
+
+	if len(job.Precision) > 0 && !displayUpdater(job.Updater.Type) {
+		log.Printf("Updater %s keeps prices at full precision, precision is ignored", job.Updater.Type)
+	}
+
+	return &jobRunner{
+		job:      job,
+		oracle:   quoteOracle,
+		fxOracle: oracle.ECB{},
+		updater:  priceUpdater,
+	}, nil
+}
+
+func (runner jobRunner) run(ctx context.Context) error {
+	switch runner.job.Kind {
+	case config.FundInfoJob:
+		return runner.runFundInfo(ctx)
+	case config.FundDividendJob:
+		return runner.runFundDividends(ctx)
+	}
+
+	if fundOracle, ok := runner.oracle.(oracle.ThaiSec); ok {
+		if !fundOracle.Calendar.Covers(time.Now()) {
+			log.Printf("Holiday calendar only lists holidays up to %d, later holidays are treated as trading days; pass an up to date one with --holiday-calendar", fundOracle.Calendar.LastYear())
+		}
+
+		if runner.job.Oracle.SkipNoNewNav {
+			expected, reason, err := fundOracle.NewNAVExpected()
+			if err != nil {
+				return fmt.Errorf("couldn't check fund trading day: %w", err)
+			}
+			if !expected {
+				log.Printf("No new NAV expected today (%s), skip updating", reason)
+				return nil
+			}
+		}
+	}
+
+	quoteItems, err := runner.oracle.GetQuoteItems(ctx, runner.job.Oracle.Targets)
+	var partial *oracle.PartialError
+	if errors.As(err, &partial) {
+		log.Printf("Couldn't retrieve quote data for some targets, keeping their last known values: %s", err.Error())
+	} else if err != nil {
+		return fmt.Errorf("couldn't retrieve quote data from oracle: %w", err)
+	}
+
+	if len(runner.job.FX.Currencies) > 0 {
+		rates, err := runner.fxOracle.GetRates(ctx)
+		if err != nil {
+			return fmt.Errorf("couldn't retrieve fx rates: %w", err)
+		}
+
+		quoteItems, err = oracle.ConvertQuoteItems(quoteItems, rates, runner.job.FX.Currencies)
+		if err != nil {
+			log.Printf("Couldn't convert some quotes, keeping them in their own currency: %s", err.Error())
+		}
+	}
+
+	precision := runner.job.Precision
+	if !displayUpdater(runner.job.Updater.Type) {
+		precision = nil
+	}
+
+	tradingPairs := createTradingPairs(quoteItems, precision)
+	if partial != nil {
+		tradingPairs = append(tradingPairs, createStalePairs(partial.Targets())...)
+	}
+
+	if err := runner.updater.UpdatePrice(ctx, tradingPairs); err != nil {
+		return fmt.Errorf("couldn't update price: %w", err)
+	}
+
+	if partial != nil {
+		return fmt.Errorf("couldn't retrieve quote data from oracle: %w", partial)
+	}
+
+	return nil
+}
+
+func (runner jobRunner) runFundInfo(ctx context.Context) error {
+	metadataOracle, ok := runner.oracle.(oracle.FundMetadataOracle)
+	if !ok {
+		return fmt.Errorf("oracle %s doesn't provide fund info", runner.job.Oracle.Type)
+	}
+	metadataUpdater, ok := runner.updater.(updater.FundMetadataUpdater)
+	if !ok {
+		return fmt.Errorf("updater %s doesn't support fund info", runner.job.Updater.Type)
+	}
+
+	funds, err := metadataOracle.GetFundMetadata(ctx, runner.job.Oracle.Targets)
+	var partial *oracle.PartialError
+	if errors.As(err, &partial) {
+		log.Printf("Couldn't retrieve fund info for some funds, keeping their last known values: %s", err.Error())
+	} else if err != nil {
+		return fmt.Errorf("couldn't retrieve fund info from oracle: %w", err)
+	}
+
+	fundMetadata := createFundMetadata(funds)
+	if partial != nil {
+		fundMetadata = append(fundMetadata, createStaleFundMetadata(partial.Targets())...)
+	}
+
+	if err := metadataUpdater.UpdateFundMetadata(ctx, fundMetadata); err != nil {
+		return fmt.Errorf("couldn't update fund info: %w", err)
+	}
+
+	if partial != nil {
+		return fmt.Errorf("couldn't retrieve fund info from oracle: %w", partial)
+	}
+
+	return nil
+}
+
+func (runner jobRunner) runFundDividends(ctx context.Context) error {
+	dividendOracle, ok := runner.oracle.(oracle.FundDividendOracle)
+	if !ok {
+		return fmt.Errorf("oracle %s doesn't provide fund dividends", runner.job.Oracle.Type)
+	}
+	dividendUpdater, ok := runner.updater.(updater.FundDividendUpdater)
+	if !ok {
+		return fmt.Errorf("updater %s doesn't support fund dividends", runner.job.Updater.Type)
+	}
+
+	dividends, err := dividendOracle.GetFundDividends(ctx, runner.job.Oracle.Targets)
+	var partial *oracle.PartialError
+	if errors.As(err, &partial) {
+		log.Printf("Couldn't retrieve dividend history for some funds, keeping their last known values: %s", err.Error())
+	} else if err != nil {
+		return fmt.Errorf("couldn't retrieve fund dividends from oracle: %w", err)
+	}
+
+	fundDividends := createFundDividends(dividends)
+	if partial != nil {
+		fundDividends = append(fundDividends, createStaleFundDividends(partial.Targets())...)
+	}
+
+	if err := dividendUpdater.UpdateFundDividends(ctx, fundDividends); err != nil {
+		return fmt.Errorf("couldn't update fund dividends: %w", err)
+	}
+
+	if partial != nil {
+		return fmt.Errorf("couldn't retrieve fund dividends from oracle: %w", partial)
+	}
+
+	return nil
+}
+
+func newOracle(cfg config.Oracle, registry *oracle.SymbolRegistry) (oracle.Oracle, error) {
+	switch cfg.Type {
+	case fallback:
+		providers, err := newProviders(cfg, registry)
+		if err != nil {
+			return nil, err
+		}
+		return oracle.Fallback{Providers: providers, QuoteCurrencies: cfg.QuoteCurrencies}, nil
+	case median:
+		// Providers only cross-check prices quoted in the same currency
+		if len(cfg.QuoteCurrencies) == 0 {
+			cfg.QuoteCurrencies = []string{medianDefaultQuote}
+		}
+		providers, err := newProviders(cfg, registry)
+		if err != nil {
+			return nil, err
+		}
+		return oracle.Median{Providers: providers, MaxDeviationPercent: cfg.MaxDeviationPercent}, nil
+	}
+
+	baseOracle, err := newBaseOracle(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	targets, err := registry.Targets(cfg.Type, cfg.Targets)
+	if err != nil {
+		return nil, err
+	}
+	if len(targets) == 0 {
+		return baseOracle, nil
+	}
+
+	return oracle.Fallback{
+		Providers: []oracle.Provider{
+			{Name: cfg.Type, Oracle: baseOracle, Targets: targets},
+		},
+		QuoteCurrencies: cfg.QuoteCurrencies,
+	}, nil
+}
+
+func newBaseOracle(cfg config.Oracle) (oracle.Oracle, error) {
+	switch cfg.Type {
+	case coinGecko:
+		return oracle.CoinGecko{QuoteCurrencies: cfg.QuoteCurrencies}, nil
+	case coinMarketCap:
+		return oracle.CMC{APIKey: cfg.APIKey, QuoteCurrencies: cfg.QuoteCurrencies}, nil
+	case thaiSec:
+		calendar, err := newThaiCalendar(cfg.HolidayCalendar)
+		if err != nil {
+			return nil, err
+		}
+		return oracle.ThaiSec{
+			FundFactAPIKey:      cfg.FundFactAPIKey,
+			FundDailyInfoAPIKey: cfg.FundDailyInfoAPIKey,
+			NavLookbackDays:     cfg.NavLookbackDays,
+			Concurrency:         cfg.Concurrency,
+			RequestsPerSecond:   cfg.RequestsPerSecond,
+			FundCachePath:       cfg.FundCachePath,
+			FundCacheTTL:        cfg.FundCacheTTL,
+			Calendar:            calendar,
+		}, nil
+	case stooq:
+		return oracle.Stooq{Currencies: cfg.Currencies}, nil
+	case ecb:
+		return oracle.ECB{}, nil
+	case thaiGold:
+		return oracle.ThaiGold{URL: cfg.URL}, nil
+	case binance:
+		return oracle.Binance{QuoteCurrencies: cfg.QuoteCurrencies, USDTAsUSD: cfg.USDTAsUSD}, nil
+	case kraken:
+		return oracle.Kraken{QuoteCurrencies: cfg.QuoteCurrencies}, nil
+	case bitkub:
+		return oracle.Bitkub{QuoteCurrencies: cfg.QuoteCurrencies}, nil
+	default:
+		return nil, fmt.Errorf("unmatched oracle %s", cfg.Type)
+	}
+}
+
+func newProviders(cfg config.Oracle, registry *oracle.SymbolRegistry) ([]oracle.Provider, error) {
+	if len(cfg.Providers) == 0 {
+		return nil, fmt.Errorf("%s oracle requires providers", cfg.Type)
+	}
+
+	var providers []oracle.Provider
+	for _, providerCfg := range cfg.Providers {
+		if len(providerCfg.QuoteCurrencies) == 0 {
+			providerCfg.QuoteCurrencies = cfg.QuoteCurrencies
+		}
+
+		var providerOracle oracle.Oracle
+		var err error
+		switch providerCfg.Type {
+		case fallback, median:
+			providerOracle, err = newOracle(providerCfg, registry)
+		default:
+			providerOracle, err = newBaseOracle(providerCfg)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		targets, err := registry.Targets(providerCfg.Type, cfg.Targets)
+		if err != nil {
+			return nil, err
+		}
+
+		positionalTargets, err := mapTargetsByPosition(cfg.Targets, providerCfg.Targets)
+		if err != nil {
+			return nil, fmt.Errorf("provider=%s %w", providerCfg.Type, err)
+		}
+		for target, providerTarget := range positionalTargets {
+			targets[target] = providerTarget
+		}
+
+		providers = append(providers, oracle.Provider{
+			Name:    providerCfg.Type,
+			Oracle:  providerOracle,
+			Targets: targets,
+		})
+	}
+
+	return providers, nil
+}
+
+// mapTargetsByPosition pairs the job targets with a provider's own identifiers
+// for the same assets, listed in the same order.
+func mapTargetsByPosition(targets, providerTargets []string) (map[string]string, error) {
+	if len(providerTargets) == 0 {
+		return nil, nil
+	}
+	if len(providerTargets) != len(targets) {
+		return nil, fmt.Errorf("has %d targets, expected %d in the same order as the job targets", len(providerTargets), len(targets))
+	}
+
+	out := map[string]string{}
+	for i, target := range targets {
+		out[target] = providerTargets[i]
+	}
+	return out, nil
+}
+
+func newThaiCalendar(path string) (*oracle.Calendar, error) {
+	if path != "" {
+		return oracle.LoadCalendar(path)
+	}
+	return oracle.NewThaiCalendar()
+}
+
+// displayUpdater tells whether an updater's output is read by people, so prices
+// may be rounded. Price history and accounting databases keep full precision.
+func displayUpdater(updaterType string) bool {
+	switch updaterType {
+	case gsheetUpdaterSa, gsheetUpdaterOauth, csvFileUpdater, jsonFileUpdater:
+		return true
+	}
+	return false
+}
+
+func newUpdater(cfg config.Updater) (updater.Updater, error) {
+	switch cfg.Type {
+	case gsheetUpdaterSa, gsheetUpdaterOauth:
+		if cfg.SheetID == "" {
+			return nil, fmt.Errorf("google sheet ID is required for updater %s", cfg.Type)
+		}
+	case csvFileUpdater, jsonFileUpdater, ledgerUpdater, beancountUpdater:
+		if cfg.Path == "" {
+			return nil, fmt.Errorf("file path is required for updater %s", cfg.Type)
+		}
+	case sqliteUpdater, postgresUpdater:
+		if cfg.DSN == "" {
+			return nil, fmt.Errorf("database DSN is required for updater %s", cfg.Type)
+		}
+	}
+
+	switch cfg.Type {
+	case gsheetUpdaterSa:
+		priceUpdater := updater.NewGoogleSheet(
+			cfg.ServiceAccountPath,
+			cfg.SheetID,
+			cfg.Range,
+		)
+		priceUpdater.Merge = cfg.Merge
+		priceUpdater.HistorySheet = cfg.HistoryTab
+		priceUpdater.MarketColumns = cfg.MarketColumns
+		priceUpdater.FundInfoSheet = cfg.FundInfoTab
+		priceUpdater.DividendSheet = cfg.DividendTab
+		return priceUpdater, nil
+	case gsheetUpdaterOauth:
+		priceUpdater, err := updater.NewGoogleSheetOAuth(
+			cfg.OAuthCredentialPath,
+			cfg.OAuthTokenPath,
+			cfg.SheetID,
+			cfg.Range,
+		)
+		if err != nil {
+			return nil, err
+		}
+		priceUpdater.Merge = cfg.Merge
+		priceUpdater.HistorySheet = cfg.HistoryTab
+		priceUpdater.MarketColumns = cfg.MarketColumns
+		priceUpdater.FundInfoSheet = cfg.FundInfoTab
+		priceUpdater.DividendSheet = cfg.DividendTab
+		return priceUpdater, nil
+	case csvFileUpdater:
+		return updater.CSVFile{Path: cfg.Path, Append: cfg.Append}, nil
+	case jsonFileUpdater:
+		return updater.JSONFile{Path: cfg.Path, Append: cfg.Append}, nil
+	case ledgerUpdater:
+		return updater.PriceDB{Path: cfg.Path, Format: updater.LedgerFormat}, nil
+	case beancountUpdater:
+		return updater.PriceDB{Path: cfg.Path, Format: updater.BeancountFormat}, nil
+	case sqliteUpdater:
+		return updater.SQLDatabase{Driver: updater.SQLiteDriver, DSN: cfg.DSN}, nil
+	case postgresUpdater:
+		return updater.SQLDatabase{Driver: updater.PostgresDriver, DSN: cfg.DSN}, nil
+	default:
+		return nil, fmt.Errorf("unmatched updater %s", cfg.Type)
+	}
+}
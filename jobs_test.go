@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/koromo-wd/priceupdater/config"
+	"github.com/koromo-wd/priceupdater/oracle"
+	"github.com/koromo-wd/priceupdater/updater"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubOracle struct {
+	items []oracle.QuoteItem
+	err   error
+}
+
+func (stub stubOracle) GetQuoteItems(ctx context.Context, queryTargets []string) ([]oracle.QuoteItem, error) {
+	return stub.items, stub.err
+}
+
+type recordingUpdater struct {
+	tradingPairs *[]updater.TradingPair
+}
+
+func (recorder recordingUpdater) UpdatePrice(ctx context.Context, tradingPairs []updater.TradingPair) error {
+	*recorder.tradingPairs = tradingPairs
+	return nil
+}
+
+func TestJobRunnerKeepsStaleTargets(t *testing.T) {
+	var written []updater.TradingPair
+	runner := jobRunner{
+		job: config.Job{Oracle: config.Oracle{Targets: []string{"KFSDIV", "SCBNK225"}}},
+		oracle: stubOracle{
+			items: []oracle.QuoteItem{{Symbol: "KFSDIV", BaseCurrency: "THB", Price: decimal.NewFromInt(15)}},
+			err:   &oracle.PartialError{Failed: []oracle.TargetError{{Target: "SCBNK225", Err: errors.New("fund not found")}}},
+		},
+		updater: recordingUpdater{tradingPairs: &written},
+	}
+
+	err := runner.run(context.Background())
+
+	var partial *oracle.PartialError
+	assert.True(t, errors.As(err, &partial))
+	assert.Len(t, written, 2)
+	assert.Equal(t, "KFSDIV", written[0].BaseSymbol)
+	assert.Equal(t, updater.TradingPair{BaseSymbol: "SCBNK225", Stale: true}, written[1])
+}
+
+func TestJobRunnerPrecision(t *testing.T) {
+	for updaterType, expected := range map[string]string{
+		csvFileUpdater: "104523.12",
+		sqliteUpdater:  "104523.12345678",
+		ledgerUpdater:  "104523.12345678",
+	} {
+		var written []updater.TradingPair
+		runner := jobRunner{
+			job: config.Job{
+				Precision: map[string]int32{"BTC": 2},
+				Updater:   config.Updater{Type: updaterType},
+			},
+			oracle: stubOracle{
+				items: []oracle.QuoteItem{{Symbol: "BTC", BaseCurrency: "USD", Price: decimal.RequireFromString("104523.12345678")}},
+			},
+			updater: recordingUpdater{tradingPairs: &written},
+		}
+
+		assert.NoError(t, runner.run(context.Background()))
+		assert.Equal(t, expected, written[0].Price.String(), updaterType)
+	}
+}
+
+func TestJobRunnerOracleFailure(t *testing.T) {
+	var written []updater.TradingPair
+	runner := jobRunner{
+		oracle:  stubOracle{err: errors.New("request returns statusCode=500")},
+		updater: recordingUpdater{tradingPairs: &written},
+	}
+
+	assert.Error(t, runner.run(context.Background()))
+	assert.Nil(t, written)
+}
+
+type stubFundOracle struct {
+	funds     []oracle.FundMetadata
+	dividends []oracle.FundDividend
+	err       error
+}
+
+func (stub stubFundOracle) GetQuoteItems(ctx context.Context, queryTargets []string) ([]oracle.QuoteItem, error) {
+	return nil, stub.err
+}
+
+func (stub stubFundOracle) GetFundMetadata(ctx context.Context, funds []string) ([]oracle.FundMetadata, error) {
+	return stub.funds, stub.err
+}
+
+func (stub stubFundOracle) GetFundDividends(ctx context.Context, funds []string) ([]oracle.FundDividend, error) {
+	return stub.dividends, stub.err
+}
+
+type recordingFundMetadataUpdater struct {
+	recordingUpdater
+	funds *[]updater.FundMetadata
+}
+
+func (recorder recordingFundMetadataUpdater) UpdateFundMetadata(ctx context.Context, funds []updater.FundMetadata) error {
+	*recorder.funds = funds
+	return nil
+}
+
+func TestJobRunnerFundInfo(t *testing.T) {
+	var written []updater.FundMetadata
+	runner := jobRunner{
+		job: config.Job{Kind: config.FundInfoJob, Oracle: config.Oracle{Targets: []string{"KFSDIV", "SCBNK225"}}},
+		oracle: stubFundOracle{
+			funds: []oracle.FundMetadata{{Fund: "KFSDIV", RiskSpectrum: "5", Source: "thaisec"}},
+			err:   &oracle.PartialError{Failed: []oracle.TargetError{{Target: "SCBNK225", Err: errors.New("fund not found")}}},
+		},
+		updater: recordingFundMetadataUpdater{funds: &written},
+	}
+
+	err := runner.run(context.Background())
+
+	var partial *oracle.PartialError
+	assert.True(t, errors.As(err, &partial))
+	assert.Equal(t, []updater.FundMetadata{
+		{Fund: "KFSDIV", RiskSpectrum: "5", Source: "thaisec"},
+		{Fund: "SCBNK225", Stale: true},
+	}, written)
+}
+
+func TestJobRunnerFundInfoUnsupported(t *testing.T) {
+	var written []updater.TradingPair
+	runner := jobRunner{
+		job:     config.Job{Kind: config.FundInfoJob, Oracle: config.Oracle{Type: "stooq"}},
+		oracle:  stubOracle{},
+		updater: recordingUpdater{tradingPairs: &written},
+	}
+
+	assert.EqualError(t, runner.run(context.Background()), "oracle stooq doesn't provide fund info")
+}
+
+type recordingFundDividendUpdater struct {
+	recordingUpdater
+	dividends *[]updater.FundDividend
+}
+
+func (recorder recordingFundDividendUpdater) UpdateFundDividends(ctx context.Context, dividends []updater.FundDividend) error {
+	*recorder.dividends = dividends
+	return nil
+}
+
+func TestJobRunnerFundDividends(t *testing.T) {
+	var written []updater.FundDividend
+	xdDate := time.Date(2021, time.May, 20, 0, 0, 0, 0, time.UTC)
+	runner := jobRunner{
+		job: config.Job{Kind: config.FundDividendJob, Oracle: config.Oracle{Targets: []string{"KFSDIV"}}},
+		oracle: stubFundOracle{
+			dividends: []oracle.FundDividend{{Fund: "KFSDIV", XDDate: xdDate, AmountPerUnit: decimal.RequireFromString("0.25"), Currency: "THB", Source: "thaisec"}},
+		},
+		updater: recordingFundDividendUpdater{dividends: &written},
+	}
+
+	assert.NoError(t, runner.run(context.Background()))
+	assert.Equal(t, []updater.FundDividend{{Fund: "KFSDIV", XDDate: xdDate, AmountPerUnit: decimal.RequireFromString("0.25"), Currency: "THB", Source: "thaisec"}}, written)
+}
+
+func TestJobRunnerFundDividendsKeepsStaleFunds(t *testing.T) {
+	var written []updater.FundDividend
+	runner := jobRunner{
+		job: config.Job{Kind: config.FundDividendJob, Oracle: config.Oracle{Targets: []string{"KFSDIV"}}},
+		oracle: stubFundOracle{
+			err: &oracle.PartialError{Failed: []oracle.TargetError{{Target: "KFSDIV", Err: errors.New("fund not found")}}},
+		},
+		updater: recordingFundDividendUpdater{dividends: &written},
+	}
+
+	err := runner.run(context.Background())
+
+	var partial *oracle.PartialError
+	assert.True(t, errors.As(err, &partial))
+	assert.Equal(t, []updater.FundDividend{{Fund: "KFSDIV", Stale: true}}, written)
+}
@@ -2,132 +2,415 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"strconv"
+	"strings"
 
+	"github.com/koromo-wd/priceupdater/config"
 	"github.com/koromo-wd/priceupdater/oracle"
 	"github.com/koromo-wd/priceupdater/updater"
+	"github.com/shopspring/decimal"
 	"gopkg.in/alecthomas/kingpin.v2"
 )
 
 const version = "1.4.0"
 const coinGecko = "coingecko"
 const coinMarketCap = "coinmarketcap"
+const thaiSec = "thaisec"
+const stooq = "stooq"
+const ecb = "ecb"
+const thaiGold = "thaigold"
+const binance = "binance"
+const kraken = "kraken"
+const bitkub = "bitkub"
+const fallback = "fallback"
+const median = "median"
+const medianDefaultQuote = "USD"
 const gsheetUpdaterSa = "gsheet-sa"
 const gsheetUpdaterOauth = "gsheet-oauth"
+const csvFileUpdater = "csv"
+const jsonFileUpdater = "json"
+const ledgerUpdater = "ledger"
+const beancountUpdater = "beancount"
+const sqliteUpdater = "sqlite"
+const postgresUpdater = "postgres"
 
 var (
-	flagUpdater              = kingpin.Flag("updater", "updater to use").Envar("UPDATER").Default(gsheetUpdaterOauth).String()
-	googleSheetSAPath        = kingpin.Flag("gsheet-sa-path", "Path to Google Sheet service account token").Envar("GSHEET_SA_PATH").Default("/app/sa.json").String()
-	googleSheetOauthCredPath = kingpin.Flag("gsheet-oauth-cred-path", "Path to Google Sheet oauth credential").Envar("GSHEET_OAUTH_CRED_PATH").Default("/app/oauth-cred.json").String()
-	googleSheetOauthTokPath  = kingpin.Flag("gsheet-oauth-token-path", "Path to Google Sheet stored token").Envar("GSHEET_OAUTH_TOKEN_PATH").Default("/tmp/oauth-token.json").String()
-	googleSheetID            = kingpin.Flag("gsheet-id", "Google Sheet ID").Envar("GSHEET_ID").Required().String()
-	googleSheetRange         = kingpin.Flag("gsheet-range", "Google Sheet range to work on").Envar("GSHEET_RANGE").Default("Sheet1!A1:B").String()
+	flagUpdater              = kingpin.Flag("updater", "updater to use").PlaceHolder(gsheetUpdaterOauth + "/" + gsheetUpdaterSa + "/" + csvFileUpdater + "/" + jsonFileUpdater + "/" + ledgerUpdater + "/" + beancountUpdater + "/" + sqliteUpdater + "/" + postgresUpdater).Envar("UPDATER").Default(gsheetUpdaterOauth).String()
+	googleSheetSAPath        = kingpin.Flag("gsheet-sa-path", "Path to Google Sheet service account token").Envar("GSHEET_SA_PATH").Default(config.DefaultGoogleSheetSAPath).String()
+	googleSheetOauthCredPath = kingpin.Flag("gsheet-oauth-cred-path", "Path to Google Sheet oauth credential").Envar("GSHEET_OAUTH_CRED_PATH").Default(config.DefaultGoogleSheetOAuthCredPath).String()
+	googleSheetOauthTokPath  = kingpin.Flag("gsheet-oauth-token-path", "Path to Google Sheet stored token").Envar("GSHEET_OAUTH_TOKEN_PATH").Default(config.DefaultGoogleSheetOAuthTokenPath).String()
+	googleSheetID            = kingpin.Flag("gsheet-id", "Google Sheet ID").Envar("GSHEET_ID").String()
+	googleSheetRange         = kingpin.Flag("gsheet-range", "Google Sheet range to work on").Envar("GSHEET_RANGE").Default(config.DefaultGoogleSheetRange).String()
+	googleSheetHistoryTab    = kingpin.Flag("gsheet-history-tab", "Google Sheet tab to append one history row per pair per run, created if missing (disabled if empty)").Envar("GSHEET_HISTORY_TAB").String()
+	googleSheetMarketColumns = kingpin.Flag("gsheet-market-columns", "Also write Bid, Ask, 24h Change %, 24h Volume, Market Cap, 24h High and 24h Low columns when the oracle provides them").Envar("GSHEET_MARKET_COLUMNS").Bool()
+	googleSheetFundInfoTab   = kingpin.Flag("gsheet-fund-info-tab", "Google Sheet tab written by the fund info command, created if missing").Envar("GSHEET_FUND_INFO_TAB").Default(config.DefaultGoogleSheetFundInfoTab).String()
+	googleSheetDividendTab   = kingpin.Flag("gsheet-dividend-tab", "Google Sheet tab written by the fund dividend command, created if missing").Envar("GSHEET_DIVIDEND_TAB").Default(config.DefaultGoogleSheetDividendTab).String()
+	googleSheetMerge         = kingpin.Flag("gsheet-merge", "Update Price/Updated Time of matching pairs in place and append new pairs instead of clearing the range").Envar("GSHEET_MERGE").Bool()
+	filePath                 = kingpin.Flag("file-path", "Path of the file written by the csv/json/ledger/beancount updater").Envar("FILE_PATH").String()
+	dbDSN                    = kingpin.Flag("db-dsn", "Database file path (sqlite) or connection string (postgres) used by the sql updaters").Envar("DB_DSN").String()
+	fileAppend               = kingpin.Flag("file-append", "Append to the file written by the csv/json updater instead of replacing it").Envar("FILE_APPEND").Bool()
+	fxCurrencies             = kingpin.Flag("fx-currency", "List of currencies every quote is also converted into using ECB reference rates (e.g. THB)").Envar("FX_CURRENCY").Strings()
+	displayPrecision         = kingpin.Flag("precision", "Decimal places to round the prices of an asset or pair to in sheet, csv and json outputs (e.g. BTC=2, SCBNK225/THB=4), full precision is kept if unset").PlaceHolder("SYMBOL=PLACES").Envar("PRECISION").StringMap()
+	symbolMapPath            = kingpin.Flag("symbol-map", "Path to a CSV (canonical,provider,id) mapping canonical symbols to provider identifiers, merged over the bundled map").Envar("SYMBOL_MAP").String()
+	configPath               = kingpin.Flag("config", "Path to a YAML file declaring the jobs to run, used by the run and serve commands").Envar("CONFIG").String()
+
+	runCommand   = kingpin.Command("run", "Run every job declared in the config file")
+	serveCommand = kingpin.Command("serve", "Keep running and execute the jobs declared in the config file on their schedules")
 
 	cryptoCommand            = kingpin.Command("crypto", "Update crypto price")
-	flagCryptoOracle         = cryptoCommand.Flag("crypto-oracle", "Crypto oracle").PlaceHolder(coinGecko + "/" + coinMarketCap).Envar("CRYPTO_ORACLE").Default(coinGecko).String()
-	coinGeckoTargetCryptoIDs = cryptoCommand.Flag("coingecko-crypto-ids", "List of target Crypto IDs, used for CoinGecko").Envar("COINGECKO_CRYPTO_IDS").Default("bitcoin", "ethereum").Strings()
-	cmcCryptoSymbols         = cryptoCommand.Flag("crypto-symbols", "List of target Crypto symbols, used for CoinMarketCap").Envar("CMC_CRYPTO_SYMBOLS").Default("BTC", "ETH").Strings()
+	flagCryptoOracle         = cryptoCommand.Flag("crypto-oracle", "Crypto oracle, or a comma separated list of oracles combined by --crypto-aggregation").PlaceHolder(coinGecko + "/" + coinMarketCap + "/" + binance + "/" + kraken + "/" + bitkub).Envar("CRYPTO_ORACLE").Default(coinGecko).String()
+	cryptoAggregation        = cryptoCommand.Flag("crypto-aggregation", "How multiple crypto oracles are combined: try them in order or take the median price").PlaceHolder(fallback+"/"+median).Envar("CRYPTO_AGGREGATION").Default(fallback).Enum(fallback, median)
+	cryptoMaxDeviation       = cryptoCommand.Flag("crypto-max-deviation", "Drop oracles deviating from the median by more than this percentage (0 keeps all)").Envar("CRYPTO_MAX_DEVIATION").Default("5").Float64()
+	coinGeckoTargetCryptoIDs = cryptoCommand.Flag("coingecko-crypto-ids", "List of CoinGecko IDs overriding the symbol map, in the same order as --crypto-symbols").Envar("COINGECKO_CRYPTO_IDS").Strings()
+	cryptoSymbols            = cryptoCommand.Flag("crypto-symbols", "List of target Crypto symbols, translated for each oracle by the symbol map").Envar("CMC_CRYPTO_SYMBOLS").Default("BTC", "ETH").Strings()
+	cryptoQuoteCurrencies    = cryptoCommand.Flag("quote-currency", "List of currencies to quote crypto prices in (e.g. USD,THB,EUR), each oracle's own default if not set").Envar("QUOTE_CURRENCY").Strings()
 	cmcAPIKey                = cryptoCommand.Flag("cmc-apikey", "CoinMarketCap API Key").Envar("CMC_API_KEY").String()
+	binanceUSDTAsUSD         = cryptoCommand.Flag("binance-usdt-as-usd", "Read the USDT market on Binance when quoting in USD, Binance has no USD markets").Envar("BINANCE_USDT_AS_USD").Bool()
 
-	fundCommand            = kingpin.Command("fund", "Update mutual fund price")
+	fundCommand            = kingpin.Command("fund", "Update mutual fund price, information or dividend history")
+	fundPriceCommand       = fundCommand.Command("price", "Update mutual fund price").Default()
+	fundInfoCommand        = fundCommand.Command("info", "Update mutual fund risk spectrum, total expense ratio, dividend policy, AMC and inception date")
+	fundDividendCommand    = fundCommand.Command("dividend", "Update mutual fund dividend history")
 	thaiSecFundDailyAPIKey = fundCommand.Flag("thsec-fdaily-apikey", "Thai Sec Fund Daily Info API Key").Envar("THSEC_FDAILY_API_KEY").String()
 	thaiSecFundFactAPIKey  = fundCommand.Flag("thsec-ffact-apikey", "Thai Sec Fund Fact API Key").Envar("THSEC_FFACT_API_KEY").String()
 	thaiSecFundNames       = fundCommand.Flag("thsec-fund-names", "List of target fund names, used for Thai Sec API").Envar("THSEC_FUND_NAMES").Strings()
+	thaiSecNavLookbackDays = fundCommand.Flag("thsec-nav-lookback-days", "Number of business days to step back when looking for the latest published NAV").Envar("THSEC_NAV_LOOKBACK_DAYS").Default("10").Int()
+	thaiSecConcurrency     = fundCommand.Flag("thsec-concurrency", "Number of funds fetched at the same time").Envar("THSEC_CONCURRENCY").Default("4").Int()
+	thaiSecRequestsPerSec  = fundCommand.Flag("thsec-requests-per-second", "Maximum requests per second sent with each Thai SEC API key").Envar("THSEC_REQUESTS_PER_SECOND").Default("5").Float64()
+	thaiSecFundCachePath   = fundCommand.Flag("thsec-fund-cache-path", "Path of the file caching fund project IDs between runs").Envar("THSEC_FUND_CACHE_PATH").Default(config.DefaultFundCachePath).String()
+	thaiSecFundCacheTTL    = fundCommand.Flag("thsec-fund-cache-ttl", "How long a cached fund project ID is used before it is looked up again").Envar("THSEC_FUND_CACHE_TTL").Default(config.DefaultFundCacheTTL.String()).Duration()
+	thaiSecSkipNoNewNav    = fundCommand.Flag("thsec-skip-no-new-nav", "Skip the update when no new NAV is expected today (weekend or Thai market holiday)").Envar("THSEC_SKIP_NO_NEW_NAV").Bool()
+	holidayCalendarPath    = fundCommand.Flag("holiday-calendar", "Path to a CSV (date,name) or iCal file of Thai market holidays, bundled calendar is used if not set").Envar("HOLIDAY_CALENDAR").String()
+
+	stockCommand    = kingpin.Command("stock", "Update stock and ETF price")
+	stockSymbols    = stockCommand.Flag("stock-symbols", "List of target stock symbols with market suffix (e.g. aapl.us), used for Stooq").Envar("STOCK_SYMBOLS").Strings()
+	stockCurrencies = stockCommand.Flag("stock-currency", "Currency of a symbol when it differs from its market's usual one (e.g. vusa.uk=GBP, as .uk is quoted in pence by default)").PlaceHolder("SYMBOL=CURRENCY").Envar("STOCK_CURRENCY").StringMap()
+
+	goldCommand = kingpin.Command("gold", "Update Thai gold price")
+	goldTypes   = goldCommand.Flag("gold-types", "List of gold types to update, each yields a buy and a sell price").PlaceHolder(oracle.GoldBar+","+oracle.GoldOrnament).Envar("GOLD_TYPES").Default(oracle.GoldBar, oracle.GoldOrnament).Strings()
+	goldURL     = goldCommand.Flag("gold-url", "URL of the JSON mirror of the goldtraders.or.th announcement").Envar("GOLD_URL").String()
 )
 
 func main() {
 	kingpin.Version(version)
 	ctx := context.Background()
-	var quoteItems []oracle.QuoteItem
-	var err error
 
-	switch kingpin.Parse() {
+	command := kingpin.Parse()
 
-	case cryptoCommand.FullCommand():
-		log.Print("Updating Crypto price")
-		quoteItems = getCryptoQuoteItems(ctx)
+	switch command {
+	case runCommand.FullCommand():
+		cfg := loadConfig()
+		registry := loadSymbolRegistry(cfg.SymbolMap)
 
-	case fundCommand.FullCommand():
-		log.Print("Updating mutual fund price")
-		fundOracle := oracle.ThaiSec{
-			FundFactAPIKey:      *thaiSecFundFactAPIKey,
-			FundDailyInfoAPIKey: *thaiSecFundDailyAPIKey,
+		failed := 0
+		for _, job := range cfg.Jobs {
+			log.Printf("Running job %s", job.Name)
+			if err := runJob(ctx, job, registry); err != nil {
+				log.Printf("Job %s failed: %s", job.Name, err.Error())
+				failed++
+			}
 		}
+		if failed > 0 {
+			log.Fatalf("%d of %d jobs failed", failed, len(cfg.Jobs))
+		}
+
+		log.Print("Finish updating price")
+		return
 
-		quoteItems, err = fundOracle.GetQuoteItems(ctx, *thaiSecFundNames)
-		if err != nil {
-			log.Fatalf("Couldn't retrieve quote data from oracle: %s", err.Error())
+	case serveCommand.FullCommand():
+		cfg := loadConfig()
+		if err := serve(cfg, loadSymbolRegistry(cfg.SymbolMap)); err != nil {
+			log.Fatalf("Couldn't serve scheduled jobs: %s", err.Error())
 		}
+		return
 	}
 
-	tradingPairs := createTradingPairs(quoteItems)
+	switch command {
+	case cryptoCommand.FullCommand():
+		log.Print("Updating Crypto price")
+	case fundPriceCommand.FullCommand():
+		log.Print("Updating mutual fund price")
+	case fundInfoCommand.FullCommand():
+		log.Print("Updating mutual fund information")
+	case fundDividendCommand.FullCommand():
+		log.Print("Updating mutual fund dividend history")
+	case stockCommand.FullCommand():
+		log.Print("Updating stock price")
+	case goldCommand.FullCommand():
+		log.Print("Updating gold price")
+	}
 
-	priceUpdater := getPriceUpdater()
-	if err := priceUpdater.UpdatePrice(ctx, tradingPairs); err != nil {
+	if err := runJob(ctx, jobFromFlags(command), loadSymbolRegistry(*symbolMapPath)); err != nil {
 		log.Fatalf("Couldn't update price: %s", err.Error())
 	}
 
 	log.Print("Finish updating price")
 }
 
-func getCryptoQuoteItems(ctx context.Context) []oracle.QuoteItem {
-	var cryptoOracle oracle.Oracle
-	var targetCryptos []string
+func loadConfig() *config.Config {
+	if *configPath == "" {
+		log.Fatal("Config file is required")
+	}
 
-	switch *flagCryptoOracle {
-	case coinGecko:
-		cryptoOracle = oracle.CoinGecko{}
-		targetCryptos = *coinGeckoTargetCryptoIDs
-	case coinMarketCap:
-		cryptoOracle = oracle.CMC{APIKey: *cmcAPIKey}
-		targetCryptos = *cmcCryptoSymbols
-	default:
-		log.Fatalf("Unmatched crypto oracle %s\n", *flagCryptoOracle)
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatalf("Couldn't load config: %s", err.Error())
 	}
 
-	quoteItems, err := cryptoOracle.GetQuoteItems(ctx, targetCryptos)
+	return cfg
+}
+
+func loadSymbolRegistry(path string) *oracle.SymbolRegistry {
+	var registry *oracle.SymbolRegistry
+	var err error
+
+	if path != "" {
+		registry, err = oracle.LoadSymbolRegistry(path)
+	} else {
+		registry, err = oracle.NewSymbolRegistry()
+	}
 	if err != nil {
-		log.Fatalf("Couldn't retrieve quote data from oracle: %s", err.Error())
+		log.Fatalf("Couldn't load symbol map: %s", err.Error())
 	}
 
-	return quoteItems
+	return registry
 }
 
-func getPriceUpdater() updater.Updater {
-	switch *flagUpdater {
-	case gsheetUpdaterSa:
-		return updater.NewGoogleSheet(
-			*googleSheetSAPath,
-			*googleSheetID,
-			*googleSheetRange,
-		)
-	case gsheetUpdaterOauth:
-		priceUpdater, err := updater.NewGoogleSheetOAuth(
-			*googleSheetOauthCredPath,
-			*googleSheetOauthTokPath,
-			*googleSheetID,
-			*googleSheetRange,
-		)
-		if err != nil {
-			log.Fatalf("Couldn't initialize updater: %s", err.Error())
+func jobFromFlags(command string) config.Job {
+	precision, err := parsePrecision(*displayPrecision)
+	if err != nil {
+		log.Fatalf("Couldn't parse precision: %s", err.Error())
+	}
+
+	job := config.Job{
+		Name:      command,
+		Kind:      config.PriceJob,
+		FX:        config.FX{Currencies: splitCommaSeparated(*fxCurrencies)},
+		Precision: precision,
+		Updater: config.Updater{
+			Type:                *flagUpdater,
+			SheetID:             *googleSheetID,
+			Range:               *googleSheetRange,
+			ServiceAccountPath:  *googleSheetSAPath,
+			OAuthCredentialPath: *googleSheetOauthCredPath,
+			OAuthTokenPath:      *googleSheetOauthTokPath,
+			Merge:               *googleSheetMerge,
+			HistoryTab:          *googleSheetHistoryTab,
+			MarketColumns:       *googleSheetMarketColumns,
+			FundInfoTab:         *googleSheetFundInfoTab,
+			DividendTab:         *googleSheetDividendTab,
+			Path:                *filePath,
+			Append:              *fileAppend,
+			DSN:                 *dbDSN,
+		},
+	}
+
+	switch command {
+	case cryptoCommand.FullCommand():
+		symbols := splitCommaSeparated(*cryptoSymbols)
+
+		var providers []config.Oracle
+		for _, oracleType := range splitCommaSeparated([]string{*flagCryptoOracle}) {
+			providers = append(providers, cryptoOracleFromFlags(oracleType))
+		}
+
+		switch {
+		case len(providers) == 1 && len(providers[0].Targets) > 0:
+			job.Oracle = providers[0]
+		case len(providers) == 1:
+			job.Oracle = providers[0]
+			job.Oracle.Targets = symbols
+		case len(providers) > 1:
+			job.Oracle = config.Oracle{
+				Type:                *cryptoAggregation,
+				Targets:             symbols,
+				Providers:           providers,
+				MaxDeviationPercent: *cryptoMaxDeviation,
+			}
+		}
+	case fundPriceCommand.FullCommand(), fundInfoCommand.FullCommand(), fundDividendCommand.FullCommand():
+		switch command {
+		case fundInfoCommand.FullCommand():
+			job.Kind = config.FundInfoJob
+		case fundDividendCommand.FullCommand():
+			job.Kind = config.FundDividendJob
+		}
+		job.Oracle = config.Oracle{
+			Type:                thaiSec,
+			Targets:             splitCommaSeparated(*thaiSecFundNames),
+			FundFactAPIKey:      *thaiSecFundFactAPIKey,
+			FundDailyInfoAPIKey: *thaiSecFundDailyAPIKey,
+			NavLookbackDays:     *thaiSecNavLookbackDays,
+			Concurrency:         *thaiSecConcurrency,
+			RequestsPerSecond:   *thaiSecRequestsPerSec,
+			FundCachePath:       *thaiSecFundCachePath,
+			FundCacheTTL:        *thaiSecFundCacheTTL,
+			SkipNoNewNav:        *thaiSecSkipNoNewNav,
+			HolidayCalendar:     *holidayCalendarPath,
+		}
+	case stockCommand.FullCommand():
+		job.Oracle = config.Oracle{
+			Type:       stooq,
+			Targets:    splitCommaSeparated(*stockSymbols),
+			Currencies: *stockCurrencies,
+		}
+	case goldCommand.FullCommand():
+		job.Oracle = config.Oracle{
+			Type:    thaiGold,
+			Targets: splitCommaSeparated(*goldTypes),
+			URL:     *goldURL,
 		}
-		return priceUpdater
-	default:
-		log.Fatalf("Unmatched updater %s\n", *flagUpdater)
 	}
 
-	return nil
+	return job
 }
 
-func createTradingPairs(quoteItems []oracle.QuoteItem) []updater.TradingPair {
+func cryptoOracleFromFlags(oracleType string) config.Oracle {
+	cfg := config.Oracle{
+		Type:            oracleType,
+		QuoteCurrencies: splitCommaSeparated(*cryptoQuoteCurrencies),
+	}
+
+	switch oracleType {
+	case coinGecko:
+		cfg.Targets = splitCommaSeparated(*coinGeckoTargetCryptoIDs)
+	case coinMarketCap:
+		cfg.APIKey = *cmcAPIKey
+	case binance:
+		cfg.USDTAsUSD = *binanceUSDTAsUSD
+	}
+
+	return cfg
+}
+
+func createTradingPairs(quoteItems []oracle.QuoteItem, precision map[string]int32) []updater.TradingPair {
 	var out []updater.TradingPair
 	for _, v := range quoteItems {
+		places, round := displayPlaces(precision, v.Symbol, v.BaseCurrency)
+		roundPrice := func(price *decimal.Decimal) *decimal.Decimal {
+			if price == nil || !round {
+				return price
+			}
+			rounded := price.Round(places)
+			return &rounded
+		}
+
 		out = append(out, updater.TradingPair{
 			BaseSymbol:  v.Symbol,
 			QuoteSymbol: v.BaseCurrency,
-			Price:       v.Price,
+			Price:       *roundPrice(&v.Price),
 			UpdatedTime: v.LastUpdated,
+			Source:      v.Source,
+
+			Bid:              roundPrice(v.Bid),
+			Ask:              roundPrice(v.Ask),
+			ChangePercent24h: v.ChangePercent24h,
+			Volume24h:        v.Volume24h,
+			MarketCap:        v.MarketCap,
+			High24h:          roundPrice(v.High24h),
+			Low24h:           roundPrice(v.Low24h),
+		})
+	}
+	return out
+}
+
+func createFundMetadata(funds []oracle.FundMetadata) []updater.FundMetadata {
+	var out []updater.FundMetadata
+	for _, v := range funds {
+		out = append(out, updater.FundMetadata{
+			Fund:              v.Fund,
+			Name:              v.Name,
+			AMC:               v.AMC,
+			RiskSpectrum:      v.RiskSpectrum,
+			TotalExpenseRatio: v.TotalExpenseRatio,
+			DividendPolicy:    v.DividendPolicy,
+			InceptionDate:     v.InceptionDate,
+			UpdatedTime:       v.LastUpdated,
+			Source:            v.Source,
 		})
 	}
 	return out
 }
+
+func createStaleFundMetadata(funds []string) []updater.FundMetadata {
+	var out []updater.FundMetadata
+	for _, fund := range funds {
+		out = append(out, updater.FundMetadata{Fund: fund, Stale: true})
+	}
+	return out
+}
+
+func createFundDividends(dividends []oracle.FundDividend) []updater.FundDividend {
+	var out []updater.FundDividend
+	for _, v := range dividends {
+		out = append(out, updater.FundDividend{
+			Fund:          v.Fund,
+			XDDate:        v.XDDate,
+			PaymentDate:   v.PaymentDate,
+			AmountPerUnit: v.AmountPerUnit,
+			Currency:      v.Currency,
+			Source:        v.Source,
+		})
+	}
+	return out
+}
+
+func createStaleFundDividends(funds []string) []updater.FundDividend {
+	var out []updater.FundDividend
+	for _, fund := range funds {
+		out = append(out, updater.FundDividend{Fund: fund, Stale: true})
+	}
+	return out
+}
+
+// createStalePairs marks every quote of a target stale, or a single pair for a
+// target written as BASE/QUOTE.
+func createStalePairs(targets []string) []updater.TradingPair {
+	var out []updater.TradingPair
+	for _, target := range targets {
+		pair := updater.TradingPair{BaseSymbol: target, Stale: true}
+		if parts := strings.SplitN(target, "/", 2); len(parts) == 2 {
+			pair.BaseSymbol, pair.QuoteSymbol = parts[0], parts[1]
+		}
+		out = append(out, pair)
+	}
+	return out
+}
+
+// displayPlaces looks a pair up by BASE/QUOTE first, then by its base symbol.
+func displayPlaces(precision map[string]int32, base, quote string) (int32, bool) {
+	for _, name := range []string{base + "/" + quote, base} {
+		for key, places := range precision {
+			if strings.EqualFold(key, name) {
+				return places, true
+			}
+		}
+	}
+	return 0, false
+}
+
+func parsePrecision(values map[string]string) (map[string]int32, error) {
+	out := map[string]int32{}
+	for key, value := range values {
+		places, err := strconv.ParseInt(strings.TrimSpace(value), 10, 32)
+		if err != nil || places < 0 {
+			return nil, fmt.Errorf("invalid decimal places %q for %s", value, key)
+		}
+		out[strings.TrimSpace(key)] = int32(places)
+	}
+	return out, nil
+}
+
+func splitCommaSeparated(values []string) []string {
+	var out []string
+	for _, v := range values {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				out = append(out, item)
+			}
+		}
+	}
+	return out
+}
@@ -4,7 +4,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/koromo-wd/priceupdater/config"
 	"github.com/koromo-wd/priceupdater/oracle"
+	"github.com/koromo-wd/priceupdater/updater"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -15,18 +18,19 @@ func TestCreateTradingPairs(t *testing.T) {
 			Name:         "A Token",
 			LastUpdated:  time.UnixMilli(1),
 			BaseCurrency: "USD",
-			Price:        1,
+			Price:        decimal.NewFromInt(1),
 		},
 		{
 			Symbol:       "B",
 			Name:         "B Token",
 			LastUpdated:  time.UnixMilli(3),
 			BaseCurrency: "USD",
-			Price:        0.8,
+			Price:        decimal.RequireFromString("0.8"),
+			Source:       "coingecko",
 		},
 	}
 
-	pairs := createTradingPairs(quoteItems)
+	pairs := createTradingPairs(quoteItems, nil)
 
 	for i, pair := range pairs {
 		quoteItem := quoteItems[i]
@@ -34,5 +38,96 @@ func TestCreateTradingPairs(t *testing.T) {
 		assert.Equal(t, "USD", pair.QuoteSymbol)
 		assert.Equal(t, quoteItem.Price, pair.Price)
 		assert.Equal(t, quoteItem.LastUpdated, pair.UpdatedTime)
+		assert.Equal(t, quoteItem.Source, pair.Source)
 	}
 }
+
+func TestCreateTradingPairsPrecision(t *testing.T) {
+	bid := decimal.RequireFromString("104523.125")
+	volume := decimal.RequireFromString("1234.5678")
+	quoteItems := []oracle.QuoteItem{
+		{Symbol: "BTC", BaseCurrency: "USD", Price: decimal.RequireFromString("104523.12345678"), Bid: &bid, Volume24h: &volume},
+		{Symbol: "BTC", BaseCurrency: "THB", Price: decimal.RequireFromString("3456789.5678")},
+		{Symbol: "SHIB", BaseCurrency: "USD", Price: decimal.RequireFromString("0.00001234567")},
+	}
+
+	pairs := createTradingPairs(quoteItems, map[string]int32{"btc": 2, "BTC/THB": 0})
+
+	assert.Equal(t, "104523.12", pairs[0].Price.String())
+	assert.Equal(t, "104523.13", pairs[0].Bid.String())
+	assert.Equal(t, "1234.5678", pairs[0].Volume24h.String())
+	assert.Equal(t, "3456790", pairs[1].Price.String())
+	assert.Equal(t, "0.00001234567", pairs[2].Price.String())
+	assert.Equal(t, "104523.125", bid.String())
+}
+
+func TestParsePrecision(t *testing.T) {
+	precision, err := parsePrecision(map[string]string{"BTC": "2", "SCBNK225/THB": " 4"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int32{"BTC": 2, "SCBNK225/THB": 4}, precision)
+
+	_, err = parsePrecision(map[string]string{"BTC": "two"})
+	assert.Error(t, err)
+}
+
+func TestSplitCommaSeparated(t *testing.T) {
+	assert.Equal(t, []string{"aapl.us", "msft.us", "vwrl.uk"}, splitCommaSeparated([]string{"aapl.us, msft.us", "vwrl.uk,"}))
+	assert.Nil(t, splitCommaSeparated(nil))
+}
+
+func TestCreateStalePairs(t *testing.T) {
+	assert.Equal(t, []updater.TradingPair{
+		{BaseSymbol: "SCBNK225", Stale: true},
+		{BaseSymbol: "BTC", QuoteSymbol: "THB", Stale: true},
+	}, createStalePairs([]string{"SCBNK225", "BTC/THB"}))
+}
+
+func TestMapTargetsByPosition(t *testing.T) {
+	targets, err := mapTargetsByPosition([]string{"BTC", "ETH"}, []string{"bitcoin", "ethereum"})
+	if err != nil {
+		t.Fail()
+	}
+	assert.Equal(t, map[string]string{"BTC": "bitcoin", "ETH": "ethereum"}, targets)
+
+	targets, err = mapTargetsByPosition([]string{"BTC", "ETH"}, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, targets)
+
+	_, err = mapTargetsByPosition([]string{"BTC", "ETH"}, []string{"bitcoin"})
+	assert.Error(t, err)
+}
+
+func TestNewOracleTranslatesCanonicalSymbols(t *testing.T) {
+	registry, _ := oracle.NewSymbolRegistry()
+
+	result, err := newOracle(config.Oracle{Type: coinGecko, Targets: []string{"BTC"}}, registry)
+	if err != nil {
+		t.Fail()
+	}
+	assert.Equal(t, oracle.Fallback{Providers: []oracle.Provider{
+		{Name: coinGecko, Oracle: oracle.CoinGecko{}, Targets: map[string]string{"BTC": "bitcoin"}},
+	}}, result)
+
+	result, err = newOracle(config.Oracle{Type: coinMarketCap, Targets: []string{"BTC"}}, registry)
+	if err != nil {
+		t.Fail()
+	}
+	assert.Equal(t, oracle.CMC{}, result)
+}
+
+func TestNewOracleMedianSharesQuoteCurrency(t *testing.T) {
+	registry, _ := oracle.NewSymbolRegistry()
+
+	result, err := newOracle(config.Oracle{
+		Type:      median,
+		Targets:   []string{"BTC"},
+		Providers: []config.Oracle{{Type: coinGecko}, {Type: binance}},
+	}, registry)
+	if err != nil {
+		t.FailNow()
+	}
+
+	providers := result.(oracle.Median).Providers
+	assert.Equal(t, oracle.CoinGecko{QuoteCurrencies: []string{"USD"}}, providers[0].Oracle)
+	assert.Equal(t, oracle.Binance{QuoteCurrencies: []string{"USD"}}, providers[1].Oracle)
+}
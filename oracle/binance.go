@@ -0,0 +1,98 @@
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Binance reads spot tickers. Binance has no USD markets: with USDTAsUSD, a USD
+// quote reads the USDT market instead, with the source naming that market.
+type Binance struct {
+	QuoteCurrencies []string
+	USDTAsUSD       bool
+}
+
+type binanceTicker struct {
+	Symbol             string          `json:"symbol"`
+	LastPrice          decimal.Decimal `json:"lastPrice"`
+	BidPrice           decimal.Decimal `json:"bidPrice"`
+	AskPrice           decimal.Decimal `json:"askPrice"`
+	PriceChangePercent decimal.Decimal `json:"priceChangePercent"`
+	HighPrice          decimal.Decimal `json:"highPrice"`
+	LowPrice           decimal.Decimal `json:"lowPrice"`
+	QuoteVolume        decimal.Decimal `json:"quoteVolume"`
+	CloseTime          int64           `json:"closeTime"`
+}
+
+const binanceTickerURL = "https://api.binance.com/api/v3/ticker/24hr"
+const binanceSymbolQuery = "symbol"
+const binanceDefaultQuote = "USDT"
+const binanceSource = "binance"
+
+func (binance Binance) GetQuoteItems(ctx context.Context, queryTargets []string) ([]QuoteItem, error) {
+	pairs, err := exchangePairs(queryTargets, binance.QuoteCurrencies, binanceDefaultQuote)
+	if err != nil {
+		return nil, err
+	}
+
+	var quoteItems []QuoteItem
+	for _, pair := range pairs {
+		base, quote := pair.base, pair.quote
+		marketQuote := binance.marketQuote(quote)
+
+		url, err := buildURLWithQueryParams(binanceTickerURL, []query{
+			{
+				key:   binanceSymbolQuery,
+				value: base + marketQuote,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var ticker binanceTicker
+		if err := getJSON(ctx, url, &ticker); err != nil {
+			return nil, fmt.Errorf("pair=%s/%s fail to request ticker from Binance: %w", base, quote, err)
+		}
+
+		quoteItem := ticker.toQuoteItem(base, quote)
+		quoteItem.ID = pair.target
+		if marketQuote != quote {
+			quoteItem.Source = fmt.Sprintf("%s(%s)", binanceSource, marketQuote)
+		}
+
+		quoteItems = append(quoteItems, quoteItem)
+	}
+
+	sortQuoteItemsAlphabeticallyASC(quoteItems)
+
+	return quoteItems, nil
+}
+
+func (ticker binanceTicker) toQuoteItem(base, quote string) QuoteItem {
+	return QuoteItem{
+		Symbol:       base,
+		Name:         base,
+		LastUpdated:  time.UnixMilli(ticker.CloseTime),
+		BaseCurrency: quote,
+		Price:        ticker.LastPrice,
+		Bid:          &ticker.BidPrice,
+		Ask:          &ticker.AskPrice,
+		Source:       binanceSource,
+
+		ChangePercent24h: &ticker.PriceChangePercent,
+		Volume24h:        &ticker.QuoteVolume,
+		High24h:          &ticker.HighPrice,
+		Low24h:           &ticker.LowPrice,
+	}
+}
+
+func (binance Binance) marketQuote(quote string) string {
+	if binance.USDTAsUSD && quote == "USD" {
+		return "USDT"
+	}
+	return quote
+}
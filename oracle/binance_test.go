@@ -0,0 +1,40 @@
+package oracle
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBinanceTickerToQuoteItem(t *testing.T) {
+	var ticker binanceTicker
+	err := json.Unmarshal([]byte(`{"symbol":"BTCUSDT","priceChangePercent":"-1.25","lastPrice":"60100.50","bidPrice":"60100.00","askPrice":"60101.00","highPrice":"61000.00","lowPrice":"59000.00","quoteVolume":"1500000.00","closeTime":1636070400000}`), &ticker)
+	if err != nil {
+		t.FailNow()
+	}
+
+	quoteItem := ticker.toQuoteItem("BTC", "USDT")
+
+	assert.Equal(t, "60100.5", quoteItem.Price.String())
+	assert.Equal(t, "60100", quoteItem.Bid.String())
+	assert.Equal(t, "60101", quoteItem.Ask.String())
+	assert.Equal(t, "-1.25", quoteItem.ChangePercent24h.String())
+	assert.Equal(t, "1500000", quoteItem.Volume24h.String())
+	assert.Equal(t, "61000", quoteItem.High24h.String())
+	assert.Equal(t, "59000", quoteItem.Low24h.String())
+	assert.Nil(t, quoteItem.MarketCap)
+	assert.Equal(t, "USDT", quoteItem.BaseCurrency)
+	assert.Equal(t, binanceSource, quoteItem.Source)
+	assert.True(t, time.UnixMilli(1636070400000).Equal(quoteItem.LastUpdated))
+
+	err = json.Unmarshal([]byte(`{"lastPrice":"n/a"}`), &ticker)
+	assert.Error(t, err)
+}
+
+func TestBinanceMarketQuote(t *testing.T) {
+	assert.Equal(t, "USD", Binance{}.marketQuote("USD"))
+	assert.Equal(t, "USDT", Binance{USDTAsUSD: true}.marketQuote("USD"))
+	assert.Equal(t, "THB", Binance{USDTAsUSD: true}.marketQuote("THB"))
+}
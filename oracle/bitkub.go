@@ -0,0 +1,90 @@
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type Bitkub struct {
+	QuoteCurrencies []string
+}
+
+type bitkubTicker struct {
+	Last          decimal.Decimal `json:"last"`
+	LowestAsk     decimal.Decimal `json:"lowestAsk"`
+	HighestBid    decimal.Decimal `json:"highestBid"`
+	PercentChange decimal.Decimal `json:"percentChange"`
+	QuoteVolume   decimal.Decimal `json:"quoteVolume"`
+	High24hr      decimal.Decimal `json:"high24hr"`
+	Low24hr       decimal.Decimal `json:"low24hr"`
+}
+
+const bitkubTickerURL = "https://api.bitkub.com/api/market/ticker"
+const bitkubSymbolQuery = "sym"
+const bitkubDefaultQuote = "THB"
+const bitkubSource = "bitkub"
+
+func (bitkub Bitkub) GetQuoteItems(ctx context.Context, queryTargets []string) ([]QuoteItem, error) {
+	pairs, err := exchangePairs(queryTargets, bitkub.QuoteCurrencies, bitkubDefaultQuote)
+	if err != nil {
+		return nil, err
+	}
+
+	var quoteItems []QuoteItem
+	for _, pair := range pairs {
+		base, quote := pair.base, pair.quote
+
+		// Bitkub names markets quote first, e.g. THB_BTC
+		symbol := quote + "_" + base
+		url, err := buildURLWithQueryParams(bitkubTickerURL, []query{
+			{
+				key:   bitkubSymbolQuery,
+				value: symbol,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var jsonRes map[string]bitkubTicker
+		if err := getJSON(ctx, url, &jsonRes); err != nil {
+			return nil, fmt.Errorf("pair=%s/%s fail to request ticker from Bitkub: %w", base, quote, err)
+		}
+
+		ticker, ok := jsonRes[symbol]
+		if !ok {
+			return nil, fmt.Errorf("pair=%s/%s market not found on Bitkub", base, quote)
+		}
+
+		quoteItem := ticker.toQuoteItem(base, quote)
+		quoteItem.ID = pair.target
+
+		quoteItems = append(quoteItems, quoteItem)
+	}
+
+	sortQuoteItemsAlphabeticallyASC(quoteItems)
+
+	return quoteItems, nil
+}
+
+func (ticker bitkubTicker) toQuoteItem(base, quote string) QuoteItem {
+	// Bitkub's ticker carries no timestamp
+	return QuoteItem{
+		Symbol:       base,
+		Name:         base,
+		LastUpdated:  time.Now(),
+		BaseCurrency: quote,
+		Price:        ticker.Last,
+		Bid:          &ticker.HighestBid,
+		Ask:          &ticker.LowestAsk,
+		Source:       bitkubSource,
+
+		ChangePercent24h: &ticker.PercentChange,
+		Volume24h:        &ticker.QuoteVolume,
+		High24h:          &ticker.High24hr,
+		Low24h:           &ticker.Low24hr,
+	}
+}
@@ -0,0 +1,28 @@
+package oracle
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBitkubTickerToQuoteItem(t *testing.T) {
+	var jsonRes map[string]bitkubTicker
+	err := json.Unmarshal([]byte(`{"THB_BTC":{"id":1,"last":2000000,"lowestAsk":2000100,"highestBid":1999900,"percentChange":1.5,"baseVolume":10,"quoteVolume":20000000,"high24hr":2010000,"low24hr":1990000}}`), &jsonRes)
+	if err != nil {
+		t.FailNow()
+	}
+
+	quoteItem := jsonRes["THB_BTC"].toQuoteItem("BTC", "THB")
+
+	assert.Equal(t, "2000000", quoteItem.Price.String())
+	assert.Equal(t, "1999900", quoteItem.Bid.String())
+	assert.Equal(t, "2000100", quoteItem.Ask.String())
+	assert.Equal(t, "1.5", quoteItem.ChangePercent24h.String())
+	assert.Equal(t, "20000000", quoteItem.Volume24h.String())
+	assert.Equal(t, "2010000", quoteItem.High24h.String())
+	assert.Equal(t, "1990000", quoteItem.Low24h.String())
+	assert.Equal(t, "THB", quoteItem.BaseCurrency)
+	assert.Equal(t, bitkubSource, quoteItem.Source)
+}
@@ -0,0 +1,154 @@
+package oracle
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+//go:embed holidays_th.csv
+var thaiHolidaysCSV []byte
+
+const calendarDateFormat = "2006-01-02"
+const icalDateFormat = "20060102"
+
+// Calendar knows which days a market is closed. A nil *Calendar only skips weekends.
+type Calendar struct {
+	holidays map[string]string
+	lastYear int
+}
+
+func NewThaiCalendar() (*Calendar, error) {
+	return parseCalendarCSV(bytes.NewReader(thaiHolidaysCSV))
+}
+
+func LoadCalendar(path string) (*Calendar, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("fail to read holiday calendar: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ics", ".ical":
+		return parseCalendarICal(bytes.NewReader(b))
+	default:
+		return parseCalendarCSV(bytes.NewReader(b))
+	}
+}
+
+// Covers tells whether t falls within the years the calendar lists holidays
+// for. Later days are only checked for weekends.
+func (c *Calendar) Covers(t time.Time) bool {
+	return c == nil || t.Year() <= c.lastYear
+}
+
+func (c *Calendar) LastYear() int {
+	if c == nil {
+		return 0
+	}
+	return c.lastYear
+}
+
+func (c *Calendar) Holiday(t time.Time) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	name, ok := c.holidays[t.Format(calendarDateFormat)]
+	return name, ok
+}
+
+func (c *Calendar) IsTradingDay(t time.Time) bool {
+	if t.Weekday() == time.Saturday || t.Weekday() == time.Sunday {
+		return false
+	}
+	_, isHoliday := c.Holiday(t)
+	return !isHoliday
+}
+
+func (c *Calendar) PreviousTradingDay(t time.Time) time.Time {
+	t = t.AddDate(0, 0, -1)
+	for !c.IsTradingDay(t) {
+		t = t.AddDate(0, 0, -1)
+	}
+	return t
+}
+
+func parseCalendarCSV(r io.Reader) (*Calendar, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("fail to parse holiday calendar: %w", err)
+	}
+
+	holidays := map[string]string{}
+	for _, record := range records {
+		date := strings.TrimSpace(record[0])
+		if _, err := time.Parse(calendarDateFormat, date); err != nil {
+			return nil, fmt.Errorf("invalid holiday date=%s: %w", date, err)
+		}
+
+		name := ""
+		if len(record) > 1 {
+			name = strings.TrimSpace(record[1])
+		}
+		holidays[date] = name
+	}
+
+	return newCalendar(holidays), nil
+}
+
+func parseCalendarICal(r io.Reader) (*Calendar, error) {
+	holidays := map[string]string{}
+	var date, name string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "BEGIN:VEVENT":
+			date, name = "", ""
+		case strings.HasPrefix(line, "DTSTART"):
+			value := line[strings.LastIndex(line, ":")+1:]
+			if len(value) < len(icalDateFormat) {
+				return nil, fmt.Errorf("invalid holiday DTSTART=%s", value)
+			}
+			t, err := time.Parse(icalDateFormat, value[:len(icalDateFormat)])
+			if err != nil {
+				return nil, fmt.Errorf("invalid holiday DTSTART=%s: %w", value, err)
+			}
+			date = t.Format(calendarDateFormat)
+		case strings.HasPrefix(line, "SUMMARY"):
+			name = line[strings.Index(line, ":")+1:]
+		case line == "END:VEVENT":
+			if date != "" {
+				holidays[date] = name
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("fail to parse holiday calendar: %w", err)
+	}
+
+	return newCalendar(holidays), nil
+}
+
+func newCalendar(holidays map[string]string) *Calendar {
+	calendar := &Calendar{holidays: holidays}
+	for date := range holidays {
+		if t, err := time.Parse(calendarDateFormat, date); err == nil && t.Year() > calendar.lastYear {
+			calendar.lastYear = t.Year()
+		}
+	}
+	return calendar
+}
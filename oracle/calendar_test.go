@@ -0,0 +1,63 @@
+package oracle
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalendarPreviousTradingDay(t *testing.T) {
+	calendar, err := parseCalendarCSV(strings.NewReader("# comment\n2021-10-25,Chulalongkorn Day (substitution)\n"))
+	if err != nil {
+		t.Fail()
+	}
+
+	friday := time.Date(2021, time.October, 22, 0, 0, 0, 0, time.UTC)
+	sunday := time.Date(2021, time.October, 24, 0, 0, 0, 0, time.UTC)
+	monday := time.Date(2021, time.October, 25, 0, 0, 0, 0, time.UTC)
+	tuesday := time.Date(2021, time.October, 26, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, friday, calendar.PreviousTradingDay(tuesday))
+	assert.Equal(t, friday, calendar.PreviousTradingDay(sunday))
+	assert.False(t, calendar.IsTradingDay(monday))
+	assert.True(t, calendar.IsTradingDay(tuesday))
+
+	var weekendOnly *Calendar
+	assert.Equal(t, monday, weekendOnly.PreviousTradingDay(tuesday))
+	assert.True(t, weekendOnly.IsTradingDay(monday))
+}
+
+func TestParseCalendarICal(t *testing.T) {
+	ical := "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"DTSTART;VALUE=DATE:20211025\r\n" +
+		"SUMMARY:Chulalongkorn Day\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	calendar, err := parseCalendarICal(strings.NewReader(ical))
+	if err != nil {
+		t.Fail()
+	}
+
+	name, ok := calendar.Holiday(time.Date(2021, time.October, 25, 0, 0, 0, 0, time.UTC))
+	assert.True(t, ok)
+	assert.Equal(t, "Chulalongkorn Day", name)
+}
+
+func TestNewThaiCalendar(t *testing.T) {
+	calendar, err := NewThaiCalendar()
+	if err != nil {
+		t.Fail()
+	}
+
+	assert.False(t, calendar.IsTradingDay(time.Date(2026, time.December, 10, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 2026, calendar.LastYear())
+	assert.True(t, calendar.Covers(time.Date(2026, time.December, 31, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, calendar.Covers(time.Date(2027, time.January, 4, 0, 0, 0, 0, time.UTC)))
+
+	var noCalendar *Calendar
+	assert.True(t, noCalendar.Covers(time.Date(2027, time.January, 4, 0, 0, 0, 0, time.UTC)))
+}
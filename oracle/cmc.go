@@ -8,14 +8,21 @@ import (
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/shopspring/decimal"
 )
 
-const cmcQuoteURL string = "https://pro-api.coinmarketcap.com/v1/cryptocurrency/quotes/latest"
 const cmcAPIKeyQuery string = "CMC_PRO_API_KEY"
 const cmcSymbolQuery string = "symbol"
+const cmcSkipInvalidQuery string = "skip_invalid"
+const cmcConvertQuery string = "convert"
+const cmcSource = "coinmarketcap"
+
+var cmcQuoteURL = "https://pro-api.coinmarketcap.com/v1/cryptocurrency/quotes/latest"
 
 type CMC struct {
-	APIKey string
+	APIKey          string
+	QuoteCurrencies []string
 }
 
 type CMCQuoteJSONResponse struct {
@@ -24,19 +31,40 @@ type CMCQuoteJSONResponse struct {
 }
 
 type CMCQuoteItem struct {
-	Id          int       `json:"id"`
-	Name        string    `json:"name"`
-	Symbol      string    `json:"symbol"`
-	Slug        string    `json:"slug"`
-	LastUpdated time.Time `json:"last_updated"`
-	Quote       struct {
-		USD struct {
-			Price float32 `json:"price"`
-		} `json:"USD"`
-	} `json:"quote"`
+	Id          int                      `json:"id"`
+	Name        string                   `json:"name"`
+	Symbol      string                   `json:"symbol"`
+	Slug        string                   `json:"slug"`
+	LastUpdated time.Time                `json:"last_updated"`
+	Quote       map[string]CMCQuotePrice `json:"quote"`
+}
+
+type CMCQuotePrice struct {
+	Price            decimal.Decimal  `json:"price"`
+	LastUpdated      time.Time        `json:"last_updated"`
+	Volume24h        *decimal.Decimal `json:"volume_24h"`
+	PercentChange24h *decimal.Decimal `json:"percent_change_24h"`
+	MarketCap        *decimal.Decimal `json:"market_cap"`
 }
 
 func (cmc CMC) GetQuoteItems(ctx context.Context, targetCryptoSymbols []string) ([]QuoteItem, error) {
+	var quoteItems []QuoteItem
+
+	// The basic plan only allows one convert currency per call
+	for _, currency := range quoteCurrenciesOrDefault(cmc.QuoteCurrencies) {
+		items, err := cmc.getQuoteItems(ctx, targetCryptoSymbols, strings.ToUpper(currency))
+		if err != nil {
+			return nil, fmt.Errorf("currency=%s %w", currency, err)
+		}
+		quoteItems = append(quoteItems, items...)
+	}
+
+	sortQuoteItemsAlphabeticallyASC(quoteItems)
+
+	return quoteItems, nil
+}
+
+func (cmc CMC) getQuoteItems(ctx context.Context, targetCryptoSymbols []string, currency string) ([]QuoteItem, error) {
 	url, err := buildURLWithQueryParams(cmcQuoteURL, []query{
 		{
 			key:   cmcAPIKeyQuery,
@@ -46,18 +74,36 @@ func (cmc CMC) GetQuoteItems(ctx context.Context, targetCryptoSymbols []string)
 			key:   cmcSymbolQuery,
 			value: strings.Join(targetCryptoSymbols, ","),
 		},
+		{
+			key:   cmcSkipInvalidQuery,
+			value: "true",
+		},
+		{
+			key:   cmcConvertQuery,
+			value: currency,
+		},
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := http.Get(url)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("fail to request quote data from CoinMarketCap")
 	}
 
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("request returns statusCode=%d", resp.StatusCode)
+	}
+
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
@@ -69,17 +115,31 @@ func (cmc CMC) GetQuoteItems(ctx context.Context, targetCryptoSymbols []string)
 	}
 
 	var quoteItems []QuoteItem
-	for _, v := range jsonRes.Data {
+	for symbol, v := range jsonRes.Data {
+		quote, ok := v.Quote[currency]
+		if !ok {
+			return nil, fmt.Errorf("symbol=%s missing quote", symbol)
+		}
+
+		lastUpdated := quote.LastUpdated
+		if lastUpdated.IsZero() {
+			lastUpdated = v.LastUpdated
+		}
+
 		quoteItems = append(quoteItems, QuoteItem{
+			ID:           symbol,
 			Symbol:       v.Symbol,
 			Name:         v.Name,
-			LastUpdated:  v.LastUpdated,
-			BaseCurrency: defaultFiat,
-			Price:        v.Quote.USD.Price,
+			LastUpdated:  lastUpdated,
+			BaseCurrency: currency,
+			Price:        quote.Price,
+			Source:       cmcSource,
+
+			ChangePercent24h: quote.PercentChange24h,
+			Volume24h:        quote.Volume24h,
+			MarketCap:        quote.MarketCap,
 		})
 	}
 
-	sortQuoteItemsAlphabeticallyASC(quoteItems)
-
 	return quoteItems, nil
 }
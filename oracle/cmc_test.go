@@ -0,0 +1,86 @@
+package oracle
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var cmcQuotesJSON = map[string]string{
+	"USD": `{"status":{"timestamp":"2021-11-05T10:30:12.345Z","error_code":0,"error_message":null},"data":{"BTC":{"id":1,"name":"Bitcoin","symbol":"BTC","slug":"bitcoin","last_updated":"2021-11-05T10:30:02.000Z","quote":{"USD":{"price":61234.5678912,"volume_24h":31234567890.12,"percent_change_24h":-1.23456789,"market_cap":1155123456789.1,"last_updated":"2021-11-05T10:30:02.000Z"}}},"ETH":{"id":1027,"name":"Ethereum","symbol":"ETH","slug":"ethereum","last_updated":"2021-11-05T10:30:02.000Z","quote":{"USD":{"price":4512.345,"volume_24h":null,"percent_change_24h":null,"market_cap":null,"last_updated":"2021-11-05T10:30:02.000Z"}}}}}`,
+	"THB": `{"status":{"timestamp":"2021-11-05T10:30:12.345Z","error_code":0,"error_message":null},"data":{"BTC":{"id":1,"name":"Bitcoin","symbol":"BTC","slug":"bitcoin","last_updated":"2021-11-05T10:30:02.000Z","quote":{"THB":{"price":2012345.678,"volume_24h":1027654321098.5,"percent_change_24h":-1.19876,"market_cap":38012345678901.2,"last_updated":"2021-11-05T10:30:05.000Z"}}},"ETH":{"id":1027,"name":"Ethereum","symbol":"ETH","slug":"ethereum","last_updated":"2021-11-05T10:30:02.000Z","quote":{"THB":{"price":148234.56,"volume_24h":null,"percent_change_24h":null,"market_cap":null,"last_updated":"2021-11-05T10:30:05.000Z"}}}}}`,
+	// Converting into a currency CoinMarketCap doesn't know leaves it out
+	"XYZ": `{"status":{"timestamp":"2021-11-05T10:30:12.345Z","error_code":0,"error_message":null},"data":{"BTC":{"id":1,"name":"Bitcoin","symbol":"BTC","slug":"bitcoin","last_updated":"2021-11-05T10:30:02.000Z","quote":{}}}}`,
+}
+
+func serveCMC(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get(cmcAPIKeyQuery) != "test-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(cmcQuotesJSON[r.URL.Query().Get(cmcConvertQuery)]))
+	}))
+	t.Cleanup(server.Close)
+
+	url := cmcQuoteURL
+	cmcQuoteURL = server.URL
+	t.Cleanup(func() { cmcQuoteURL = url })
+}
+
+func TestCMCGetQuoteItems(t *testing.T) {
+	serveCMC(t)
+
+	quoteItems, err := CMC{APIKey: "test-key", QuoteCurrencies: []string{"usd", "THB"}}.GetQuoteItems(context.Background(), []string{"BTC", "ETH"})
+	if err != nil {
+		t.FailNow()
+	}
+
+	var rows []string
+	for _, item := range quoteItems {
+		rows = append(rows, item.ID+" "+item.Symbol+"/"+item.BaseCurrency+" "+item.Price.String()+" "+item.Source)
+	}
+	assert.Equal(t, []string{
+		"BTC BTC/THB 2012345.678 coinmarketcap",
+		"BTC BTC/USD 61234.5678912 coinmarketcap",
+		"ETH ETH/THB 148234.56 coinmarketcap",
+		"ETH ETH/USD 4512.345 coinmarketcap",
+	}, rows)
+	assert.Equal(t, "Bitcoin", quoteItems[0].Name)
+	assert.True(t, time.Date(2021, time.November, 5, 10, 30, 5, 0, time.UTC).Equal(quoteItems[0].LastUpdated))
+}
+
+func TestCMCGetQuoteItemsMarketFields(t *testing.T) {
+	serveCMC(t)
+
+	quoteItems, err := CMC{APIKey: "test-key"}.GetQuoteItems(context.Background(), []string{"BTC", "ETH"})
+	if err != nil {
+		t.FailNow()
+	}
+
+	btc := quoteItems[0]
+	assert.Equal(t, "-1.23456789", btc.ChangePercent24h.String())
+	assert.Equal(t, "31234567890.12", btc.Volume24h.String())
+	assert.Equal(t, "1155123456789.1", btc.MarketCap.String())
+	assert.Nil(t, btc.High24h)
+	assert.Nil(t, btc.Low24h)
+
+	eth := quoteItems[1]
+	assert.Nil(t, eth.ChangePercent24h)
+	assert.Nil(t, eth.Volume24h)
+	assert.Nil(t, eth.MarketCap)
+}
+
+func TestCMCGetQuoteItemsMissingCurrency(t *testing.T) {
+	serveCMC(t)
+
+	_, err := CMC{APIKey: "test-key", QuoteCurrencies: []string{"USD", "XYZ"}}.GetQuoteItems(context.Background(), []string{"BTC"})
+	assert.EqualError(t, err, "currency=XYZ symbol=BTC missing quote")
+
+	_, err = CMC{APIKey: "wrong-key"}.GetQuoteItems(context.Background(), []string{"BTC"})
+	assert.EqualError(t, err, "currency=USD request returns statusCode=401")
+}
@@ -8,23 +8,51 @@ import (
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/shopspring/decimal"
 )
 
-type CoinGecko struct{}
+type CoinGecko struct {
+	QuoteCurrencies []string
+}
 
 type CoinGeckoMarketItem struct {
-	ID           string    `json:"id"`
-	Symbol       string    `json:"symbol"`
-	Name         string    `json:"name"`
-	CurrentPrice float32   `json:"current_price"`
-	LastUpdated  time.Time `json:"last_updated"`
+	ID           string          `json:"id"`
+	Symbol       string          `json:"symbol"`
+	Name         string          `json:"name"`
+	CurrentPrice decimal.Decimal `json:"current_price"`
+	LastUpdated  time.Time       `json:"last_updated"`
+
+	PriceChangePercentage24h *decimal.Decimal `json:"price_change_percentage_24h"`
+	TotalVolume              *decimal.Decimal `json:"total_volume"`
+	MarketCap                *decimal.Decimal `json:"market_cap"`
+	High24h                  *decimal.Decimal `json:"high_24h"`
+	Low24h                   *decimal.Decimal `json:"low_24h"`
 }
 
-const coinGeckoGetMarketDataURL = "https://api.coingecko.com/api/v3/coins/markets"
 const coinGeckoIDsQuery = "ids"
 const coinGeckoVSCurrencyQuery = "vs_currency"
+const coinGeckoSource = "coingecko"
+
+var coinGeckoGetMarketDataURL = "https://api.coingecko.com/api/v3/coins/markets"
 
 func (coinGecko CoinGecko) GetQuoteItems(ctx context.Context, targetCryptoIDs []string) ([]QuoteItem, error) {
+	var quoteItems []QuoteItem
+
+	for _, currency := range quoteCurrenciesOrDefault(coinGecko.QuoteCurrencies) {
+		items, err := coinGecko.getQuoteItems(ctx, targetCryptoIDs, currency)
+		if err != nil {
+			return nil, fmt.Errorf("currency=%s %w", currency, err)
+		}
+		quoteItems = append(quoteItems, items...)
+	}
+
+	sortQuoteItemsAlphabeticallyASC(quoteItems)
+
+	return quoteItems, nil
+}
+
+func (coinGecko CoinGecko) getQuoteItems(ctx context.Context, targetCryptoIDs []string, currency string) ([]QuoteItem, error) {
 	url, err := buildURLWithQueryParams(coinGeckoGetMarketDataURL, []query{
 		{
 			key:   coinGeckoIDsQuery,
@@ -32,20 +60,30 @@ func (coinGecko CoinGecko) GetQuoteItems(ctx context.Context, targetCryptoIDs []
 		},
 		{
 			key:   coinGeckoVSCurrencyQuery,
-			value: defaultFiat,
+			value: strings.ToLower(currency),
 		},
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := http.Get(url)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("fail to request market data from CoinGecko")
 	}
 
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("request returns statusCode=%d", resp.StatusCode)
+	}
+
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
@@ -59,15 +97,21 @@ func (coinGecko CoinGecko) GetQuoteItems(ctx context.Context, targetCryptoIDs []
 	var quoteItems []QuoteItem
 	for _, v := range jsonRes {
 		quoteItems = append(quoteItems, QuoteItem{
+			ID:           v.ID,
 			Symbol:       strings.ToUpper(v.Symbol),
 			Name:         v.Name,
 			LastUpdated:  v.LastUpdated,
-			BaseCurrency: defaultFiat,
+			BaseCurrency: strings.ToUpper(currency),
 			Price:        v.CurrentPrice,
+			Source:       coinGeckoSource,
+
+			ChangePercent24h: v.PriceChangePercentage24h,
+			Volume24h:        v.TotalVolume,
+			MarketCap:        v.MarketCap,
+			High24h:          v.High24h,
+			Low24h:           v.Low24h,
 		})
 	}
 
-	sortQuoteItemsAlphabeticallyASC(quoteItems)
-
 	return quoteItems, nil
 }
@@ -0,0 +1,85 @@
+package oracle
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var coinGeckoMarketsJSON = map[string]string{
+	"usd": `[{"id":"bitcoin","symbol":"btc","name":"Bitcoin","current_price":61234.56,"market_cap":1155123456789,"total_volume":31234567890,"high_24h":62000,"low_24h":60500.5,"price_change_percentage_24h":-1.2345,"last_updated":"2021-11-05T10:30:00.123Z"},{"id":"ethereum","symbol":"eth","name":"Ethereum","current_price":4512.34,"market_cap":null,"total_volume":null,"high_24h":null,"low_24h":null,"price_change_percentage_24h":null,"last_updated":"2021-11-05T10:29:58.456Z"}]`,
+	"thb": `[{"id":"bitcoin","symbol":"btc","name":"Bitcoin","current_price":2012345.67,"market_cap":38012345678901,"total_volume":1027654321098,"high_24h":2040000,"low_24h":1990000,"price_change_percentage_24h":-1.1987,"last_updated":"2021-11-05T10:30:00.123Z"},{"id":"ethereum","symbol":"eth","name":"Ethereum","current_price":148234.5,"market_cap":null,"total_volume":null,"high_24h":null,"low_24h":null,"price_change_percentage_24h":null,"last_updated":"2021-11-05T10:29:58.456Z"}]`,
+}
+
+func serveCoinGecko(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := coinGeckoMarketsJSON[r.URL.Query().Get(coinGeckoVSCurrencyQuery)]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid vs_currency"}`))
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	url := coinGeckoGetMarketDataURL
+	coinGeckoGetMarketDataURL = server.URL
+	t.Cleanup(func() { coinGeckoGetMarketDataURL = url })
+}
+
+func TestCoinGeckoGetQuoteItems(t *testing.T) {
+	serveCoinGecko(t)
+
+	quoteItems, err := CoinGecko{QuoteCurrencies: []string{"USD", "thb"}}.GetQuoteItems(context.Background(), []string{"bitcoin", "ethereum"})
+	if err != nil {
+		t.FailNow()
+	}
+
+	var rows []string
+	for _, item := range quoteItems {
+		rows = append(rows, item.ID+" "+item.Symbol+"/"+item.BaseCurrency+" "+item.Price.String()+" "+item.Source)
+	}
+	assert.Equal(t, []string{
+		"bitcoin BTC/THB 2012345.67 coingecko",
+		"bitcoin BTC/USD 61234.56 coingecko",
+		"ethereum ETH/THB 148234.5 coingecko",
+		"ethereum ETH/USD 4512.34 coingecko",
+	}, rows)
+	assert.Equal(t, "Bitcoin", quoteItems[0].Name)
+	assert.True(t, time.Date(2021, time.November, 5, 10, 30, 0, 123000000, time.UTC).Equal(quoteItems[0].LastUpdated))
+}
+
+func TestCoinGeckoGetQuoteItemsMarketFields(t *testing.T) {
+	serveCoinGecko(t)
+
+	quoteItems, err := CoinGecko{}.GetQuoteItems(context.Background(), []string{"bitcoin", "ethereum"})
+	if err != nil {
+		t.FailNow()
+	}
+
+	btc := quoteItems[0]
+	assert.Equal(t, "-1.2345", btc.ChangePercent24h.String())
+	assert.Equal(t, "31234567890", btc.Volume24h.String())
+	assert.Equal(t, "1155123456789", btc.MarketCap.String())
+	assert.Equal(t, "62000", btc.High24h.String())
+	assert.Equal(t, "60500.5", btc.Low24h.String())
+	assert.Nil(t, btc.Bid)
+
+	eth := quoteItems[1]
+	assert.Nil(t, eth.ChangePercent24h)
+	assert.Nil(t, eth.Volume24h)
+	assert.Nil(t, eth.MarketCap)
+	assert.Nil(t, eth.High24h)
+}
+
+func TestCoinGeckoGetQuoteItemsMissingCurrency(t *testing.T) {
+	serveCoinGecko(t)
+
+	_, err := CoinGecko{QuoteCurrencies: []string{"USD", "XYZ"}}.GetQuoteItems(context.Background(), []string{"bitcoin"})
+	assert.EqualError(t, err, "currency=XYZ request returns statusCode=400")
+}
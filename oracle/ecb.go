@@ -0,0 +1,224 @@
+package oracle
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const ecbDailyRatesURL = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
+const ecbBaseCurrency = "EUR"
+const ecbTz = "Europe/Berlin"
+const ecbSource = "ecb"
+
+// minorCurrencies are quoted by some markets in hundredths of a currency ECB
+// publishes, e.g. LSE prices in pence.
+var minorCurrencies = map[string]string{
+	"GBX": "GBP",
+	"ZAC": "ZAR",
+	"ILA": "ILS",
+}
+
+// ECB reads the European Central Bank daily euro reference rates. Besides
+// serving rates for currency normalisation it works as an Oracle for
+// "BASE/QUOTE" targets such as USD/THB.
+type ECB struct{}
+
+type FXRates struct {
+	Base   string
+	Date   time.Time
+	Rates  map[string]decimal.Decimal
+	Source string
+}
+
+type FXOracle interface {
+	GetRates(ctx context.Context) (*FXRates, error)
+}
+
+type ecbEnvelope struct {
+	Cube struct {
+		Cube struct {
+			Time string `xml:"time,attr"`
+			Cube []struct {
+				Currency string          `xml:"currency,attr"`
+				Rate     decimal.Decimal `xml:"rate,attr"`
+			} `xml:"Cube"`
+		} `xml:"Cube"`
+	} `xml:"Cube"`
+}
+
+func (ecb ECB) GetRates(ctx context.Context) (*FXRates, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", ecbDailyRatesURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fail to request reference rates from ECB: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("request returns statusCode=%d", resp.StatusCode)
+	}
+
+	var envelope ecbEnvelope
+	if err := xml.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return nil, err
+	}
+
+	return envelope.toFXRates()
+}
+
+func (ecb ECB) GetQuoteItems(ctx context.Context, targetPairs []string) ([]QuoteItem, error) {
+	rates, err := ecb.GetRates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var quoteItems []QuoteItem
+	for _, pair := range targetPairs {
+		base, quote, err := splitPair(pair)
+		if err != nil {
+			return nil, err
+		}
+
+		price, err := rates.Convert(decimal.NewFromInt(1), base, quote)
+		if err != nil {
+			return nil, err
+		}
+
+		quoteItems = append(quoteItems, QuoteItem{
+			ID:           pair,
+			Symbol:       base,
+			Name:         base,
+			LastUpdated:  rates.Date,
+			BaseCurrency: quote,
+			Price:        price,
+			Source:       rates.Source,
+		})
+	}
+
+	sortQuoteItemsAlphabeticallyASC(quoteItems)
+
+	return quoteItems, nil
+}
+
+func (envelope ecbEnvelope) toFXRates() (*FXRates, error) {
+	timeLoc, err := getTimeLoc(ecbTz)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reference rates are published around 16:00 CET
+	date, err := time.ParseInLocation(navDateFormat, envelope.Cube.Cube.Time, timeLoc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid reference rate date: %w", err)
+	}
+
+	rates := map[string]decimal.Decimal{ecbBaseCurrency: decimal.NewFromInt(1)}
+	for _, rate := range envelope.Cube.Cube.Cube {
+		rates[rate.Currency] = rate.Rate
+	}
+	for minor, major := range minorCurrencies {
+		if rate, ok := rates[major]; ok {
+			rates[minor] = rate.Mul(decimal.NewFromInt(100))
+		}
+	}
+
+	return &FXRates{
+		Base:   ecbBaseCurrency,
+		Date:   date.Add(16 * time.Hour),
+		Rates:  rates,
+		Source: ecbSource,
+	}, nil
+}
+
+func (rates FXRates) Convert(amount decimal.Decimal, from, to string) (decimal.Decimal, error) {
+	fromRate, ok := rates.Rates[strings.ToUpper(from)]
+	if !ok || fromRate.IsZero() {
+		return decimal.Zero, fmt.Errorf("no %s rate for %s", rates.Source, from)
+	}
+	toRate, ok := rates.Rates[strings.ToUpper(to)]
+	if !ok {
+		return decimal.Zero, fmt.Errorf("no %s rate for %s", rates.Source, to)
+	}
+
+	return amount.Mul(toRate).Div(fromRate), nil
+}
+
+// ConvertQuoteItems adds a copy of every quote item converted into each target
+// currency, unless the item is already quoted in it. All conversions use the
+// same rates snapshot. Items in a currency without a rate, such as USDT, are
+// kept unconverted and listed in the returned error; the returned items are
+// complete either way.
+func ConvertQuoteItems(quoteItems []QuoteItem, rates *FXRates, targetCurrencies []string) ([]QuoteItem, error) {
+	var skipped []string
+	existing := map[string]bool{}
+	for _, item := range quoteItems {
+		existing[item.Symbol+"/"+item.BaseCurrency] = true
+	}
+
+	out := append([]QuoteItem{}, quoteItems...)
+	for _, item := range quoteItems {
+		for _, currency := range targetCurrencies {
+			currency = strings.ToUpper(currency)
+			if existing[item.Symbol+"/"+currency] {
+				continue
+			}
+
+			price, err := rates.Convert(item.Price, item.BaseCurrency, currency)
+			if err != nil {
+				skipped = append(skipped, fmt.Sprintf("symbol=%s %s", item.Symbol, err.Error()))
+				continue
+			}
+
+			converted := item
+			converted.BaseCurrency = currency
+			converted.Price = price
+			converted.Bid = rates.convertOptional(item.Bid, item.BaseCurrency, currency)
+			converted.Ask = rates.convertOptional(item.Ask, item.BaseCurrency, currency)
+			converted.Volume24h = rates.convertOptional(item.Volume24h, item.BaseCurrency, currency)
+			converted.MarketCap = rates.convertOptional(item.MarketCap, item.BaseCurrency, currency)
+			converted.High24h = rates.convertOptional(item.High24h, item.BaseCurrency, currency)
+			converted.Low24h = rates.convertOptional(item.Low24h, item.BaseCurrency, currency)
+			converted.Source = item.Source + "+" + rates.Source
+			existing[item.Symbol+"/"+currency] = true
+			out = append(out, converted)
+		}
+	}
+
+	sortQuoteItemsAlphabeticallyASC(out)
+
+	if len(skipped) > 0 {
+		return out, fmt.Errorf("%d conversions skipped: %s", len(skipped), strings.Join(skipped, "; "))
+	}
+
+	return out, nil
+}
+
+func (rates FXRates) convertOptional(amount *decimal.Decimal, from, to string) *decimal.Decimal {
+	if amount == nil {
+		return nil
+	}
+	converted, err := rates.Convert(*amount, from, to)
+	if err != nil {
+		return nil
+	}
+	return &converted
+}
+
+func splitPair(pair string) (string, string, error) {
+	parts := strings.Split(pair, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("pair=%s expected BASE/QUOTE", pair)
+	}
+	return strings.ToUpper(parts[0]), strings.ToUpper(parts[1]), nil
+}
@@ -0,0 +1,128 @@
+package oracle
+
+import (
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+const ecbDailyXML = `<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<Cube>
+		<Cube time='2021-11-05'>
+			<Cube currency='USD' rate='1.1555'/>
+			<Cube currency='THB' rate='38.404'/>
+			<Cube currency='GBP' rate='0.8573'/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`
+
+func TestECBToFXRates(t *testing.T) {
+	var envelope ecbEnvelope
+	if err := xml.Unmarshal([]byte(ecbDailyXML), &envelope); err != nil {
+		t.FailNow()
+	}
+
+	rates, err := envelope.toFXRates()
+	if err != nil {
+		t.FailNow()
+	}
+
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	assert.True(t, time.Date(2021, time.November, 5, 16, 0, 0, 0, berlin).Equal(rates.Date))
+
+	thb, err := rates.Convert(decimal.NewFromInt(1), "USD", "THB")
+	assert.NoError(t, err)
+	assert.Equal(t, "33.2358", thb.StringFixed(4))
+
+	eur, err := rates.Convert(decimal.RequireFromString("38.404"), "thb", "EUR")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", eur.String())
+
+	_, err = rates.Convert(decimal.NewFromInt(1), "USD", "XYZ")
+	assert.Error(t, err)
+
+	gbp, err := rates.Convert(decimal.NewFromInt(7500), "GBX", "GBP")
+	assert.NoError(t, err)
+	assert.Equal(t, "75", gbp.String())
+}
+
+var testRates = map[string]decimal.Decimal{
+	"EUR": decimal.NewFromInt(1),
+	"USD": decimal.RequireFromString("1.2"),
+	"THB": decimal.NewFromInt(40),
+}
+
+func TestConvertQuoteItems(t *testing.T) {
+	rates := &FXRates{Rates: testRates, Source: "ecb"}
+	quoteItems := []QuoteItem{
+		{Symbol: "BTC", BaseCurrency: "USD", Price: decimal.NewFromInt(60000), Source: "coingecko"},
+		{Symbol: "SCBNK225", BaseCurrency: "THB", Price: decimal.RequireFromString("12.3456"), Source: "thaisec"},
+	}
+
+	result, err := ConvertQuoteItems(quoteItems, rates, []string{"thb"})
+	if err != nil {
+		t.FailNow()
+	}
+
+	var rows []string
+	for _, item := range result {
+		rows = append(rows, item.Symbol+"/"+item.BaseCurrency+" "+item.Price.String()+" "+item.Source)
+	}
+	assert.Equal(t, []string{
+		"BTC/THB 2000000 coingecko+ecb",
+		"BTC/USD 60000 coingecko",
+		"SCBNK225/THB 12.3456 thaisec",
+	}, rows)
+}
+
+func TestConvertQuoteItemsSkipsUnknownCurrency(t *testing.T) {
+	rates := &FXRates{Rates: testRates, Source: "ecb"}
+	quoteItems := []QuoteItem{
+		{Symbol: "BTC", BaseCurrency: "USDT", Price: decimal.NewFromInt(60000), Source: "binance"},
+		{Symbol: "ETH", BaseCurrency: "USD", Price: decimal.NewFromInt(3000), Source: "coingecko"},
+	}
+
+	result, err := ConvertQuoteItems(quoteItems, rates, []string{"THB"})
+	assert.EqualError(t, err, "1 conversions skipped: symbol=BTC no ecb rate for USDT")
+
+	var rows []string
+	for _, item := range result {
+		rows = append(rows, item.Symbol+"/"+item.BaseCurrency)
+	}
+	assert.Equal(t, []string{"BTC/USDT", "ETH/THB", "ETH/USD"}, rows)
+}
+
+func TestConvertQuoteItemsMarketFields(t *testing.T) {
+	rates := &FXRates{Rates: testRates, Source: "ecb"}
+	change, volume := decimal.RequireFromString("-2.5"), decimal.NewFromInt(1200)
+	quoteItems := []QuoteItem{
+		{Symbol: "BTC", BaseCurrency: "USD", Price: decimal.NewFromInt(60000), ChangePercent24h: &change, Volume24h: &volume},
+	}
+
+	result, err := ConvertQuoteItems(quoteItems, rates, []string{"EUR"})
+	if err != nil {
+		t.FailNow()
+	}
+
+	assert.Equal(t, "EUR", result[0].BaseCurrency)
+	assert.Equal(t, "50000", result[0].Price.String())
+	assert.Equal(t, "-2.5", result[0].ChangePercent24h.String())
+	assert.Equal(t, "1000", result[0].Volume24h.String())
+	assert.Nil(t, result[0].Bid)
+	assert.Equal(t, "1200", result[1].Volume24h.String())
+}
+
+func TestSplitPair(t *testing.T) {
+	base, quote, err := splitPair("usd/thb")
+	assert.NoError(t, err)
+	assert.Equal(t, "USD", base)
+	assert.Equal(t, "THB", quote)
+
+	_, _, err = splitPair("USDTHB")
+	assert.Error(t, err)
+}
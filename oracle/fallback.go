@@ -0,0 +1,104 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Fallback asks each provider in order for the targets that previous providers
+// failed to return, so one throttled or incomplete provider doesn't fail the run.
+// With QuoteCurrencies, a target is only complete once it is quoted in each of
+// them. Targets no provider returned are reported in a *PartialError along with
+// the quotes of the others, as BASE/QUOTE when only some currencies are missing.
+type Fallback struct {
+	Providers       []Provider
+	QuoteCurrencies []string
+}
+
+func (fallback Fallback) GetQuoteItems(ctx context.Context, queryTargets []string) ([]QuoteItem, error) {
+	var quoteItems []QuoteItem
+	var providerErrs []string
+	found := map[string]bool{}
+	remaining := queryTargets
+
+	for _, provider := range fallback.Providers {
+		if len(remaining) == 0 {
+			break
+		}
+
+		providerTargets, queryTargetOf := provider.translate(remaining)
+
+		items, err := provider.Oracle.GetQuoteItems(ctx, providerTargets)
+		if err != nil {
+			providerErrs = append(providerErrs, fmt.Sprintf("provider=%s %s", provider.Name, err.Error()))
+			var partial *PartialError
+			if !errors.As(err, &partial) {
+				continue
+			}
+		}
+
+		for _, item := range items {
+			target, ok := queryTargetOf[strings.ToUpper(item.ID)]
+			key := target + "/" + strings.ToUpper(item.BaseCurrency)
+			if !ok || found[key] {
+				continue
+			}
+			found[key] = true
+			found[target] = true
+
+			item.ID = target
+			if item.Source == "" {
+				item.Source = provider.Name
+			}
+			quoteItems = append(quoteItems, item)
+		}
+
+		var missing []string
+		for _, target := range remaining {
+			if len(fallback.missingPairs(target, found)) > 0 {
+				missing = append(missing, target)
+			}
+		}
+		if len(missing) > 0 {
+			providerErrs = append(providerErrs, fmt.Sprintf("provider=%s missing %s", provider.Name, strings.Join(missing, ",")))
+		}
+		remaining = missing
+	}
+
+	sortQuoteItemsAlphabeticallyASC(quoteItems)
+
+	if len(remaining) > 0 {
+		reason := fmt.Errorf("no provider returned quotes: %s", strings.Join(providerErrs, "; "))
+		var failed []TargetError
+		for _, target := range remaining {
+			for _, missing := range fallback.missingPairs(target, found) {
+				failed = append(failed, TargetError{Target: missing, Err: reason})
+			}
+		}
+		return quoteItems, &PartialError{Failed: failed}
+	}
+
+	return quoteItems, nil
+}
+
+// missingPairs lists what is still missing for a target: the target itself when
+// nothing was found, otherwise its BASE/QUOTE pairs in the missing currencies.
+func (fallback Fallback) missingPairs(target string, found map[string]bool) []string {
+	if !found[target] {
+		return []string{target}
+	}
+	if strings.Contains(target, "/") {
+		return nil
+	}
+
+	var missing []string
+	for _, currency := range fallback.QuoteCurrencies {
+		key := target + "/" + strings.ToUpper(currency)
+		if !found[key] {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
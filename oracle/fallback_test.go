@@ -0,0 +1,180 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubOracle struct {
+	items map[string]QuoteItem
+	err   error
+}
+
+func (stub stubOracle) GetQuoteItems(ctx context.Context, queryTargets []string) ([]QuoteItem, error) {
+	if stub.err != nil {
+		return nil, stub.err
+	}
+
+	var quoteItems []QuoteItem
+	for _, target := range queryTargets {
+		if item, ok := stub.items[target]; ok {
+			item.ID = target
+			quoteItems = append(quoteItems, item)
+		}
+	}
+	return quoteItems, nil
+}
+
+func TestFallbackGetQuoteItems(t *testing.T) {
+	fallback := Fallback{Providers: []Provider{
+		{
+			Name:   "throttled",
+			Oracle: stubOracle{err: errors.New("request returns statusCode=429")},
+		},
+		{
+			Name: "ids",
+			Oracle: stubOracle{items: map[string]QuoteItem{
+				"bitcoin": {Symbol: "BTC", Price: decimal.NewFromInt(60000)},
+			}},
+			Targets: map[string]string{"BTC": "bitcoin", "ADA": "cardano"},
+		},
+		{
+			Name: "symbols",
+			Oracle: stubOracle{items: map[string]QuoteItem{
+				"BTC": {Symbol: "BTC", Price: decimal.NewFromInt(1)},
+				"ADA": {Symbol: "ADA", Price: decimal.NewFromInt(2), Source: "symbols-api"},
+			}},
+		},
+	}}
+
+	quoteItems, err := fallback.GetQuoteItems(context.Background(), []string{"BTC", "ADA"})
+	if err != nil {
+		t.FailNow()
+	}
+
+	assert.Equal(t, []QuoteItem{
+		{ID: "ADA", Symbol: "ADA", Price: decimal.NewFromInt(2), Source: "symbols-api"},
+		{ID: "BTC", Symbol: "BTC", Price: decimal.NewFromInt(60000), Source: "ids"},
+	}, quoteItems)
+}
+
+func TestFallbackGetQuoteItemsMissing(t *testing.T) {
+	fallback := Fallback{Providers: []Provider{
+		{Name: "empty", Oracle: stubOracle{}},
+	}}
+
+	_, err := fallback.GetQuoteItems(context.Background(), []string{"BTC"})
+	assert.EqualError(t, err, "1 targets failed: target=BTC no provider returned quotes: provider=empty missing BTC")
+}
+
+func TestFallbackGetQuoteItemsKeepsFoundTargets(t *testing.T) {
+	fallback := Fallback{Providers: []Provider{
+		{Name: "symbols", Oracle: stubOracle{items: map[string]QuoteItem{
+			"BTC": {Symbol: "BTC", Price: decimal.NewFromInt(60000)},
+		}}},
+	}}
+
+	quoteItems, err := fallback.GetQuoteItems(context.Background(), []string{"BTC", "ADA"})
+
+	var partial *PartialError
+	assert.True(t, errors.As(err, &partial))
+	assert.Equal(t, []string{"ADA"}, partial.Targets())
+	assert.Equal(t, []QuoteItem{{ID: "BTC", Symbol: "BTC", Price: decimal.NewFromInt(60000), Source: "symbols"}}, quoteItems)
+}
+
+func TestFallbackGetQuoteItemsMultipleCurrencies(t *testing.T) {
+	fallback := Fallback{Providers: []Provider{
+		{Name: "multi", Oracle: multiCurrencyOracle{currencies: []string{"THB", "USD"}}},
+	}}
+
+	quoteItems, err := fallback.GetQuoteItems(context.Background(), []string{"BTC"})
+	if err != nil {
+		t.FailNow()
+	}
+
+	assert.Len(t, quoteItems, 2)
+	assert.Equal(t, "THB", quoteItems[0].BaseCurrency)
+	assert.Equal(t, "USD", quoteItems[1].BaseCurrency)
+}
+
+func TestFallbackGetQuoteItemsMissingCurrency(t *testing.T) {
+	fallback := Fallback{
+		Providers: []Provider{
+			{Name: "usd", Oracle: multiCurrencyOracle{currencies: []string{"USD"}}},
+			{Name: "thb", Oracle: multiCurrencyOracle{currencies: []string{"USD", "THB"}}},
+		},
+		QuoteCurrencies: []string{"USD", "THB"},
+	}
+
+	quoteItems, err := fallback.GetQuoteItems(context.Background(), []string{"BTC"})
+	if err != nil {
+		t.FailNow()
+	}
+
+	assert.Equal(t, []QuoteItem{
+		{ID: "BTC", Symbol: "BTC", BaseCurrency: "THB", Source: "thb"},
+		{ID: "BTC", Symbol: "BTC", BaseCurrency: "USD", Source: "usd"},
+	}, quoteItems)
+}
+
+func TestFallbackGetQuoteItemsMissingCurrencyEverywhere(t *testing.T) {
+	fallback := Fallback{
+		Providers: []Provider{
+			{Name: "usd", Oracle: multiCurrencyOracle{currencies: []string{"USD"}}},
+		},
+		QuoteCurrencies: []string{"USD", "THB"},
+	}
+
+	quoteItems, err := fallback.GetQuoteItems(context.Background(), []string{"BTC"})
+
+	var partial *PartialError
+	if !errors.As(err, &partial) {
+		t.FailNow()
+	}
+	assert.Equal(t, []string{"BTC/THB"}, partial.Targets())
+	assert.Equal(t, []QuoteItem{{ID: "BTC", Symbol: "BTC", BaseCurrency: "USD", Source: "usd"}}, quoteItems)
+}
+
+type multiCurrencyOracle struct {
+	currencies []string
+}
+
+func (stub multiCurrencyOracle) GetQuoteItems(ctx context.Context, queryTargets []string) ([]QuoteItem, error) {
+	var quoteItems []QuoteItem
+	for _, target := range queryTargets {
+		for _, currency := range stub.currencies {
+			quoteItems = append(quoteItems, QuoteItem{ID: target, Symbol: target, BaseCurrency: currency})
+		}
+	}
+	return quoteItems, nil
+}
+
+func TestFallbackGetQuoteItemsPartial(t *testing.T) {
+	partial := &PartialError{Failed: []TargetError{{Target: "ADA", Err: errors.New("fund not found")}}}
+	fallback := Fallback{Providers: []Provider{
+		{Name: "partial", Oracle: partialOracle{items: []QuoteItem{{ID: "BTC", Symbol: "BTC"}}, err: partial}},
+		{Name: "symbols", Oracle: stubOracle{items: map[string]QuoteItem{"ADA": {Symbol: "ADA"}}}},
+	}}
+
+	quoteItems, err := fallback.GetQuoteItems(context.Background(), []string{"BTC", "ADA"})
+	if err != nil {
+		t.FailNow()
+	}
+
+	assert.Len(t, quoteItems, 2)
+	assert.Equal(t, "partial", quoteItems[1].Source)
+	assert.Equal(t, "symbols", quoteItems[0].Source)
+}
+
+type partialOracle struct {
+	items []QuoteItem
+	err   error
+}
+
+func (stub partialOracle) GetQuoteItems(ctx context.Context, queryTargets []string) ([]QuoteItem, error) {
+	return stub.items, stub.err
+}
@@ -0,0 +1,128 @@
+package oracle
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// fundInfoCache keeps fund name to project lookups on disk between runs, since
+// a fund's project ID never changes.
+type fundInfoCache struct {
+	mu      sync.Mutex
+	path    string
+	ttl     time.Duration
+	entries map[string]fundInfoCacheEntry
+	dirty   bool
+}
+
+type fundInfoCacheEntry struct {
+	Info      fundInfo  `json:"info"`
+	FetchedAt time.Time `json:"fetched_at"`
+	Version   int       `json:"version,omitempty"`
+}
+
+// fundInfoCacheVersion is bumped whenever fundInfo gains fields, so entries
+// written by an older version are refetched instead of used until the TTL.
+const fundInfoCacheVersion = 2
+
+// loadFundInfoCache starts from an empty cache when the file is missing or
+// unreadable, it is rebuilt from the API as funds are looked up.
+func loadFundInfoCache(path string, ttl time.Duration) *fundInfoCache {
+	cache := &fundInfoCache{path: path, ttl: ttl, entries: map[string]fundInfoCacheEntry{}}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return cache
+	}
+
+	var entries map[string]fundInfoCacheEntry
+	if err := json.Unmarshal(b, &entries); err == nil && entries != nil {
+		cache.entries = entries
+	}
+
+	return cache
+}
+
+// get returns the cached fund info and whether it is still within the TTL. A
+// nil cache never has entries.
+func (cache *fundInfoCache) get(fundName string) (*fundInfo, bool, bool) {
+	if cache == nil {
+		return nil, false, false
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	entry, ok := cache.entries[fundName]
+	if !ok {
+		return nil, false, false
+	}
+
+	info := entry.Info
+	return &info, true, entry.Version == fundInfoCacheVersion && now().Sub(entry.FetchedAt) < cache.ttl
+}
+
+func (cache *fundInfoCache) put(fundName string, info fundInfo) {
+	if cache == nil {
+		return
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.entries[fundName] = fundInfoCacheEntry{Info: info, FetchedAt: now(), Version: fundInfoCacheVersion}
+	cache.dirty = true
+}
+
+// save writes the cache back if anything changed. The cache only saves API
+// calls, so callers ignore its error instead of failing the run.
+func (cache *fundInfoCache) save() error {
+	if cache == nil {
+		return nil
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if !cache.dirty {
+		return nil
+	}
+
+	b, err := json.MarshalIndent(cache.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cache.path), 0755); err != nil {
+		return err
+	}
+
+	// Jobs sharing the cache file each write their own temporary file
+	f, err := ioutil.TempFile(filepath.Dir(cache.path), "."+filepath.Base(cache.path)+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := f.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, cache.path); err != nil {
+		return err
+	}
+
+	cache.dirty = false
+	return nil
+}
@@ -0,0 +1,91 @@
+package oracle
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFundInfoCache(t *testing.T) {
+	fetched := time.Date(2021, time.November, 5, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return fetched }
+	defer func() { now = time.Now }()
+
+	path := filepath.Join(t.TempDir(), "cache", "funds.json")
+	cache := loadFundInfoCache(path, 24*time.Hour)
+
+	_, found, fresh := cache.get("SCBNK225")
+	assert.False(t, found)
+	assert.False(t, fresh)
+
+	cache.put("SCBNK225", fundInfo{ProjectID: "M0001_2544", ProjectABBRName: "SCBNK225"})
+	assert.NoError(t, cache.save())
+
+	now = func() time.Time { return fetched.Add(time.Hour) }
+	info, found, fresh := loadFundInfoCache(path, 24*time.Hour).get("SCBNK225")
+	assert.True(t, found)
+	assert.True(t, fresh)
+	assert.Equal(t, "M0001_2544", info.ProjectID)
+
+	now = func() time.Time { return fetched.Add(48 * time.Hour) }
+	info, found, fresh = loadFundInfoCache(path, 24*time.Hour).get("SCBNK225")
+	assert.True(t, found)
+	assert.False(t, fresh)
+	assert.Equal(t, "M0001_2544", info.ProjectID)
+}
+
+func TestFundInfoCacheRefetchesOlderVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "funds.json")
+	entries := `{"SCBNK225":{"info":{"proj_id":"M0001_2544"},"fetched_at":"` + time.Now().Format(time.RFC3339) + `"}}`
+	assert.NoError(t, ioutil.WriteFile(path, []byte(entries), 0644))
+
+	info, found, fresh := loadFundInfoCache(path, 24*time.Hour).get("SCBNK225")
+	assert.True(t, found)
+	assert.False(t, fresh)
+	assert.Equal(t, "M0001_2544", info.ProjectID)
+}
+
+func TestFundInfoCacheConcurrentSave(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "funds.json")
+
+	var wg sync.WaitGroup
+	errs := make([]error, 8)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			cache := loadFundInfoCache(path, time.Hour)
+			cache.put("SCBNK225", fundInfo{ProjectID: "M0001_2544"})
+			errs[i] = cache.save()
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+
+	files, _ := ioutil.ReadDir(dir)
+	assert.Len(t, files, 1)
+	_, found, _ := loadFundInfoCache(path, time.Hour).get("SCBNK225")
+	assert.True(t, found)
+}
+
+func TestFundInfoCacheUnreadable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "funds.json")
+	assert.NoError(t, ioutil.WriteFile(path, []byte("{not json"), 0644))
+
+	_, found, _ := loadFundInfoCache(path, time.Hour).get("SCBNK225")
+	assert.False(t, found)
+
+	var disabled *fundInfoCache
+	disabled.put("SCBNK225", fundInfo{})
+	_, found, _ = disabled.get("SCBNK225")
+	assert.False(t, found)
+	assert.NoError(t, disabled.save())
+}
@@ -0,0 +1,122 @@
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type FundDividendOracle interface {
+	GetFundDividends(ctx context.Context, funds []string) ([]FundDividend, error)
+}
+
+// A FundDividend is one payment of a fund. PaymentDate is zero when the
+// factsheet doesn't state it.
+type FundDividend struct {
+	Fund          string
+	XDDate        time.Time
+	PaymentDate   time.Time
+	AmountPerUnit decimal.Decimal
+	Currency      string
+	Source        string
+}
+
+type fundDividendDetail struct {
+	ClassABBRName string          `json:"class_abbr_name"`
+	XDDate        string          `json:"xd_date"`
+	PaymentDate   string          `json:"payment_date"`
+	DividendValue decimal.Decimal `json:"dividend_value"`
+}
+
+// GetFundDividends returns the dividend history of each fund, sorted by fund
+// then XD date. Funds that never paid one have no entry.
+func (sec ThaiSec) GetFundDividends(ctx context.Context, funds []string) ([]FundDividend, error) {
+	sec = sec.withRateLimits()
+	if sec.FundCachePath != "" {
+		sec.fundCache = loadFundInfoCache(sec.FundCachePath, sec.FundCacheTTL)
+	}
+
+	results := make([][]FundDividend, len(funds))
+	failed := sec.forEachFund(funds, func(i int) error {
+		var err error
+		results[i], err = sec.getFundDividends(ctx, funds[i])
+		return err
+	})
+
+	var out []FundDividend
+	for _, result := range results {
+		out = append(out, result...)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Fund != out[j].Fund {
+			return out[i].Fund < out[j].Fund
+		}
+		return out[i].XDDate.Before(out[j].XDDate)
+	})
+
+	_ = sec.fundCache.save()
+
+	if len(failed) > 0 {
+		return out, &PartialError{Failed: failed}
+	}
+
+	return out, nil
+}
+
+func (sec ThaiSec) getFundDividends(ctx context.Context, fundName string) ([]FundDividend, error) {
+	info, err := sec.lookupFundInfo(ctx, fundName)
+	if err != nil {
+		return nil, fmt.Errorf("fail to get fund info from Thai SEC API: %w", err)
+	}
+
+	timeLoc, err := getTimeLoc(bkkTz)
+	if err != nil {
+		return nil, err
+	}
+
+	var dividend fundDividend
+	if err := sec.getFundFact(ctx, fmt.Sprintf(fundDividendURLTemplate, info.ProjectID), &dividend); err != nil {
+		return nil, fmt.Errorf("fundID=%s fail to get dividend history: %w", info.ProjectID, err)
+	}
+
+	return dividendsOfClass(fundName, dividend.DividendDetails, info.ClassABBRName, timeLoc)
+}
+
+// dividendsOfClass keeps the payments of className along with those declared
+// for the whole project.
+func dividendsOfClass(fundName string, details []fundDividendDetail, className string, timeLoc *time.Location) ([]FundDividend, error) {
+	var out []FundDividend
+	for _, detail := range details {
+		projectWide := detail.ClassABBRName == "" || detail.ClassABBRName == "-"
+		if className != "" && !projectWide && !strings.EqualFold(detail.ClassABBRName, className) {
+			continue
+		}
+
+		xdDate, err := parseFundFactDate(detail.XDDate, timeLoc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid XD date %q: %w", detail.XDDate, err)
+		}
+
+		var paymentDate time.Time
+		if detail.PaymentDate != "" {
+			paymentDate, err = parseFundFactDate(detail.PaymentDate, timeLoc)
+			if err != nil {
+				return nil, fmt.Errorf("invalid payment date %q: %w", detail.PaymentDate, err)
+			}
+		}
+
+		out = append(out, FundDividend{
+			Fund:          fundName,
+			XDDate:        xdDate,
+			PaymentDate:   paymentDate,
+			AmountPerUnit: detail.DividendValue,
+			Currency:      thb,
+			Source:        thaiSecSource,
+		})
+	}
+	return out, nil
+}
@@ -0,0 +1,41 @@
+package oracle
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDividendsOfClass(t *testing.T) {
+	bkk, _ := time.LoadLocation("Asia/Bangkok")
+
+	var dividend fundDividend
+	assert.NoError(t, json.Unmarshal([]byte(`{
+		"dividend_policy": "จ่าย",
+		"dividend_details": [
+			{"class_abbr_name": "K-USA-A(D)", "xd_date": "2021-05-20T00:00:00", "payment_date": "2021-06-01", "dividend_value": 0.25},
+			{"class_abbr_name": "K-USA-A(A)", "xd_date": "2021-05-20", "payment_date": "2021-06-01", "dividend_value": 0.3},
+			{"class_abbr_name": "-", "xd_date": "2020-11-18", "payment_date": "", "dividend_value": "0.1500"}
+		]
+	}`), &dividend))
+
+	dividends, err := dividendsOfClass("K-USA-A(D)", dividend.DividendDetails, "K-USA-A(D)", bkk)
+	assert.NoError(t, err)
+	assert.Len(t, dividends, 2)
+	assert.Equal(t, "K-USA-A(D)", dividends[0].Fund)
+	assert.Equal(t, time.Date(2021, time.May, 20, 0, 0, 0, 0, bkk), dividends[0].XDDate)
+	assert.Equal(t, time.Date(2021, time.June, 1, 0, 0, 0, 0, bkk), dividends[0].PaymentDate)
+	assert.Equal(t, "0.25", dividends[0].AmountPerUnit.String())
+	assert.Equal(t, "THB", dividends[0].Currency)
+	assert.True(t, dividends[1].PaymentDate.IsZero())
+	assert.Equal(t, "0.15", dividends[1].AmountPerUnit.String())
+
+	dividends, err = dividendsOfClass("K-USA", dividend.DividendDetails, "", bkk)
+	assert.NoError(t, err)
+	assert.Len(t, dividends, 3)
+
+	_, err = dividendsOfClass("K-USA", []fundDividendDetail{{XDDate: "20/05/2021"}}, "", bkk)
+	assert.Error(t, err)
+}
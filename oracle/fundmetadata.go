@@ -0,0 +1,226 @@
+package oracle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type FundMetadataOracle interface {
+	GetFundMetadata(ctx context.Context, funds []string) ([]FundMetadata, error)
+}
+
+type FundMetadata struct {
+	Fund              string
+	Name              string
+	AMC               string
+	RiskSpectrum      string
+	TotalExpenseRatio *decimal.Decimal
+	DividendPolicy    string
+	InceptionDate     time.Time
+	LastUpdated       time.Time
+	Source            string
+}
+
+type fundRisk struct {
+	RiskSpectrum string `json:"risk_spectrum"`
+}
+
+type fundFee struct {
+	ClassABBRName string `json:"class_abbr_name"`
+	FeeTypeDesc   string `json:"fee_type_desc"`
+	ActualValue   string `json:"actual_value"`
+}
+
+type fundDividend struct {
+	DividendPolicy  string               `json:"dividend_policy"`
+	DividendDetails []fundDividendDetail `json:"dividend_details"`
+}
+
+type fundAMC struct {
+	UniqueID string `json:"unique_id"`
+	NameEN   string `json:"name_en"`
+	NameTH   string `json:"name_th"`
+}
+
+const fundRiskURLTemplate = "https://api.sec.or.th/FundFactsheet/fund/%s/risk"
+const fundFeeURLTemplate = "https://api.sec.or.th/FundFactsheet/fund/%s/fee"
+const fundDividendURLTemplate = "https://api.sec.or.th/FundFactsheet/fund/%s/dividend"
+const fundAMCURL = "https://api.sec.or.th/FundFactsheet/fund/amc"
+
+var totalExpenseFeeTypes = []string{"ค่าใช้จ่ายรวมทั้งหมด", "total expense"}
+
+// GetFundMetadata looks up the factsheet of each fund. Like GetQuoteItems, it
+// carries on past funds that fail and reports them in a *PartialError.
+func (sec ThaiSec) GetFundMetadata(ctx context.Context, funds []string) ([]FundMetadata, error) {
+	sec = sec.withRateLimits()
+
+	var amcs []fundAMC
+	if err := sec.getFundFact(ctx, fundAMCURL, &amcs); err != nil {
+		return nil, fmt.Errorf("fail to get asset management companies from Thai SEC API: %w", err)
+	}
+	amcNames := map[string]string{}
+	for _, amc := range amcs {
+		amcNames[amc.UniqueID] = amc.name()
+	}
+
+	results := make([]*FundMetadata, len(funds))
+	failed := sec.forEachFund(funds, func(i int) error {
+		var err error
+		results[i], err = sec.getFundMetadata(ctx, funds[i], amcNames)
+		return err
+	})
+
+	var out []FundMetadata
+	for _, result := range results {
+		if result != nil {
+			out = append(out, *result)
+		}
+	}
+
+	if len(failed) > 0 {
+		return out, &PartialError{Failed: failed}
+	}
+
+	return out, nil
+}
+
+func (sec ThaiSec) getFundMetadata(ctx context.Context, fundName string, amcNames map[string]string) (*FundMetadata, error) {
+	info, err := sec.getFundInfo(ctx, fundName)
+	if err != nil {
+		return nil, fmt.Errorf("fail to get fund info from Thai SEC API: %w", err)
+	}
+
+	timeLoc, err := getTimeLoc(bkkTz)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := FundMetadata{
+		Fund:        fundName,
+		Name:        info.ProjectNameEN,
+		AMC:         amcNames[info.AMCID],
+		LastUpdated: now().In(timeLoc),
+		Source:      thaiSecSource,
+	}
+	if metadata.Name == "" {
+		metadata.Name = info.name()
+	}
+	if metadata.AMC == "" {
+		metadata.AMC = info.AMCID
+	}
+	if info.RegisterDate != "" {
+		metadata.InceptionDate, err = parseFundFactDate(info.RegisterDate, timeLoc)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var risk fundRisk
+	if err := sec.getFundFact(ctx, fmt.Sprintf(fundRiskURLTemplate, info.ProjectID), &risk); err != nil {
+		return nil, fmt.Errorf("fundID=%s fail to get risk spectrum: %w", info.ProjectID, err)
+	}
+	metadata.RiskSpectrum = risk.RiskSpectrum
+
+	var fees []fundFee
+	if err := sec.getFundFact(ctx, fmt.Sprintf(fundFeeURLTemplate, info.ProjectID), &fees); err != nil {
+		return nil, fmt.Errorf("fundID=%s fail to get fees: %w", info.ProjectID, err)
+	}
+	metadata.TotalExpenseRatio = totalExpenseRatio(fees, info.ClassABBRName)
+
+	var dividend fundDividend
+	if err := sec.getFundFact(ctx, fmt.Sprintf(fundDividendURLTemplate, info.ProjectID), &dividend); err != nil {
+		return nil, fmt.Errorf("fundID=%s fail to get dividend policy: %w", info.ProjectID, err)
+	}
+	metadata.DividendPolicy = dividend.DividendPolicy
+
+	return &metadata, nil
+}
+
+// getFundFact reads a FundFactsheet endpoint into out, which is left untouched
+// when the API has no data for the fund.
+func (sec ThaiSec) getFundFact(ctx context.Context, url string, out interface{}) error {
+	if err := sec.fundFactLimiter.Wait(ctx); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set(apiKeyHeader, sec.FundFactAPIKey)
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("request returns statusCode=%d", resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// totalExpenseRatio picks the total expense fee of className, falling back to
+// the one declared for the whole project. It is nil when the fund doesn't
+// disclose one.
+func totalExpenseRatio(fees []fundFee, className string) *decimal.Decimal {
+	var projectRatio *decimal.Decimal
+	for _, fee := range fees {
+		if !isTotalExpenseFee(fee.FeeTypeDesc) {
+			continue
+		}
+
+		ratio, err := parsePrice(strings.TrimSpace(fee.ActualValue))
+		if err != nil {
+			continue
+		}
+
+		switch {
+		case className != "" && strings.EqualFold(fee.ClassABBRName, className):
+			return ratio
+		case fee.ClassABBRName == "" || fee.ClassABBRName == "-":
+			projectRatio = ratio
+		case className == "" && projectRatio == nil:
+			projectRatio = ratio
+		}
+	}
+	return projectRatio
+}
+
+func isTotalExpenseFee(desc string) bool {
+	desc = strings.ToLower(desc)
+	for _, feeType := range totalExpenseFeeTypes {
+		if strings.Contains(desc, feeType) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseFundFactDate reads the date part of a FundFactsheet date, which may
+// come with a time.
+func parseFundFactDate(value string, timeLoc *time.Location) (time.Time, error) {
+	if len(value) > len(navDateFormat) {
+		value = value[:len(navDateFormat)]
+	}
+	return time.ParseInLocation(navDateFormat, value, timeLoc)
+}
+
+func (amc fundAMC) name() string {
+	if amc.NameEN != "" {
+		return amc.NameEN
+	}
+	return amc.NameTH
+}
@@ -0,0 +1,38 @@
+package oracle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTotalExpenseRatio(t *testing.T) {
+	fees := []fundFee{
+		{ClassABBRName: "-", FeeTypeDesc: "ค่าธรรมเนียมการจัดการ", ActualValue: "1.0700"},
+		{ClassABBRName: "-", FeeTypeDesc: "ค่าใช้จ่ายรวมทั้งหมด", ActualValue: "1.6050"},
+		{ClassABBRName: "K-USA-A(D)", FeeTypeDesc: "ค่าใช้จ่ายรวมทั้งหมด", ActualValue: "1.5000"},
+		{ClassABBRName: "K-USA-SSF", FeeTypeDesc: "Total Expense Ratio", ActualValue: "-"},
+	}
+
+	assert.Equal(t, "1.5", totalExpenseRatio(fees, "K-USA-A(D)").String())
+	assert.Equal(t, "1.605", totalExpenseRatio(fees, "K-USA-A(A)").String())
+	assert.Equal(t, "1.605", totalExpenseRatio(fees, "K-USA-SSF").String())
+	assert.Nil(t, totalExpenseRatio(fees[:1], ""))
+	assert.Nil(t, totalExpenseRatio(nil, "K-USA-A(A)"))
+}
+
+func TestParseFundFactDate(t *testing.T) {
+	bkk, _ := time.LoadLocation("Asia/Bangkok")
+
+	date, err := parseFundFactDate("2017-05-24T00:00:00", bkk)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2017, time.May, 24, 0, 0, 0, 0, bkk), date)
+
+	date, err = parseFundFactDate("2017-05-24", bkk)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2017, time.May, 24, 0, 0, 0, 0, bkk), date)
+
+	_, err = parseFundFactDate("24/05/2017", bkk)
+	assert.Error(t, err)
+}
@@ -0,0 +1,171 @@
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type Kraken struct {
+	QuoteCurrencies []string
+}
+
+type krakenTickerResponse struct {
+	Error  []string                `json:"error"`
+	Result map[string]krakenTicker `json:"result"`
+}
+
+// krakenTicker fields are [price, whole lot volume, lot volume] for a/b,
+// [price, lot volume] for c, [today, last 24 hours] for v/p/h/l and today's
+// opening price for o.
+type krakenTicker struct {
+	Ask    []string `json:"a"`
+	Bid    []string `json:"b"`
+	Last   []string `json:"c"`
+	Volume []string `json:"v"`
+	VWAP   []string `json:"p"`
+	High   []string `json:"h"`
+	Low    []string `json:"l"`
+	Open   string   `json:"o"`
+}
+
+const krakenTickerURL = "https://api.kraken.com/0/public/Ticker"
+const krakenPairQuery = "pair"
+const krakenDefaultQuote = "USD"
+const krakenSource = "kraken"
+
+var krakenAssetNames = map[string]string{
+	"BTC":  "XBT",
+	"DOGE": "XDG",
+}
+
+func (kraken Kraken) GetQuoteItems(ctx context.Context, queryTargets []string) ([]QuoteItem, error) {
+	pairs, err := exchangePairs(queryTargets, kraken.QuoteCurrencies, krakenDefaultQuote)
+	if err != nil {
+		return nil, err
+	}
+
+	var quoteItems []QuoteItem
+	for _, pair := range pairs {
+		base, quote := pair.base, pair.quote
+
+		url, err := buildURLWithQueryParams(krakenTickerURL, []query{
+			{
+				key:   krakenPairQuery,
+				value: krakenAssetName(base) + krakenAssetName(quote),
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var jsonRes krakenTickerResponse
+		if err := getJSON(ctx, url, &jsonRes); err != nil {
+			return nil, fmt.Errorf("pair=%s/%s fail to request ticker from Kraken: %w", base, quote, err)
+		}
+
+		quoteItem, err := jsonRes.toQuoteItem(base, quote)
+		if err != nil {
+			return nil, fmt.Errorf("pair=%s/%s %w", base, quote, err)
+		}
+		quoteItem.ID = pair.target
+
+		quoteItems = append(quoteItems, *quoteItem)
+	}
+
+	sortQuoteItemsAlphabeticallyASC(quoteItems)
+
+	return quoteItems, nil
+}
+
+func (res krakenTickerResponse) toQuoteItem(base, quote string) (*QuoteItem, error) {
+	if len(res.Error) > 0 {
+		return nil, fmt.Errorf("kraken error: %s", strings.Join(res.Error, ", "))
+	}
+	if len(res.Result) != 1 {
+		return nil, fmt.Errorf("unexpected ticker result count=%d", len(res.Result))
+	}
+
+	for _, ticker := range res.Result {
+		if len(ticker.Last) == 0 || len(ticker.Bid) == 0 || len(ticker.Ask) == 0 {
+			return nil, fmt.Errorf("incomplete ticker")
+		}
+
+		last, err := parsePrice(ticker.Last[0])
+		if err != nil {
+			return nil, err
+		}
+		bid, err := parsePrice(ticker.Bid[0])
+		if err != nil {
+			return nil, err
+		}
+		ask, err := parsePrice(ticker.Ask[0])
+		if err != nil {
+			return nil, err
+		}
+
+		var volume, high, low *decimal.Decimal
+		if len(ticker.Volume) == 2 && len(ticker.VWAP) == 2 {
+			baseVolume, err := parsePrice(ticker.Volume[1])
+			if err != nil {
+				return nil, err
+			}
+			vwap, err := parsePrice(ticker.VWAP[1])
+			if err != nil {
+				return nil, err
+			}
+			quoteVolume := baseVolume.Mul(*vwap)
+			volume = &quoteVolume
+		}
+		if len(ticker.High) == 2 && len(ticker.Low) == 2 {
+			if high, err = parsePrice(ticker.High[1]); err != nil {
+				return nil, err
+			}
+			if low, err = parsePrice(ticker.Low[1]); err != nil {
+				return nil, err
+			}
+		}
+
+		// Kraken only reports the opening price of the UTC day, not 24 hours ago
+		var change *decimal.Decimal
+		if ticker.Open != "" {
+			open, err := parsePrice(ticker.Open)
+			if err != nil {
+				return nil, err
+			}
+			if !open.IsZero() {
+				percent := last.Sub(*open).Div(*open).Mul(decimal.NewFromInt(100))
+				change = &percent
+			}
+		}
+
+		// Kraken's ticker carries no timestamp
+		return &QuoteItem{
+			Symbol:       base,
+			Name:         base,
+			LastUpdated:  time.Now(),
+			BaseCurrency: quote,
+			Price:        *last,
+			Bid:          bid,
+			Ask:          ask,
+			Source:       krakenSource,
+
+			ChangePercent24h: change,
+			Volume24h:        volume,
+			High24h:          high,
+			Low24h:           low,
+		}, nil
+	}
+
+	return nil, nil
+}
+
+func krakenAssetName(asset string) string {
+	if name, ok := krakenAssetNames[asset]; ok {
+		return name
+	}
+	return asset
+}
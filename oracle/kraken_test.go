@@ -0,0 +1,40 @@
+package oracle
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKrakenTickerToQuoteItem(t *testing.T) {
+	var res krakenTickerResponse
+	err := json.Unmarshal([]byte(`{"error":[],"result":{"XXBTZUSD":{"a":["60101.0","1","1.000"],"b":["60100.0","2","2.000"],"c":["60100.5","0.01"],"v":["10","20"],"p":["60000","60050"],"h":["60500","61000"],"l":["59500","59000"],"o":"59000.0"}}}`), &res)
+	if err != nil {
+		t.FailNow()
+	}
+
+	quoteItem, err := res.toQuoteItem("BTC", "USD")
+	if err != nil {
+		t.FailNow()
+	}
+
+	assert.Equal(t, "60100.5", quoteItem.Price.String())
+	assert.Equal(t, "60100", quoteItem.Bid.String())
+	assert.Equal(t, "60101", quoteItem.Ask.String())
+	assert.Equal(t, "1201000", quoteItem.Volume24h.String())
+	assert.Equal(t, "61000", quoteItem.High24h.String())
+	assert.Equal(t, "59000", quoteItem.Low24h.String())
+	assert.Equal(t, "1.8653", quoteItem.ChangePercent24h.StringFixed(4))
+	assert.WithinDuration(t, time.Now(), quoteItem.LastUpdated, time.Minute)
+	assert.Equal(t, krakenSource, quoteItem.Source)
+
+	_, err = krakenTickerResponse{Error: []string{"EQuery:Unknown asset pair"}}.toQuoteItem("BTC", "XYZ")
+	assert.EqualError(t, err, "kraken error: EQuery:Unknown asset pair")
+}
+
+func TestKrakenAssetName(t *testing.T) {
+	assert.Equal(t, "XBT", krakenAssetName("BTC"))
+	assert.Equal(t, "ETH", krakenAssetName("ETH"))
+}
@@ -0,0 +1,195 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/shopspring/decimal"
+)
+
+// Median queries every provider for the same targets and reports the median
+// price, dropping providers that deviate from it by more than
+// MaxDeviationPercent (0 keeps every provider). Targets no provider returned
+// are reported in a *PartialError along with the consensus of the others.
+type Median struct {
+	Providers           []Provider
+	MaxDeviationPercent float64
+}
+
+type Consensus struct {
+	QuoteItem     QuoteItem
+	Sources       []string
+	Excluded      []string
+	SpreadPercent float64
+}
+
+type medianSample struct {
+	source string
+	item   QuoteItem
+}
+
+type providerResult struct {
+	items         []QuoteItem
+	queryTargetOf map[string]string
+	err           error
+}
+
+func (median Median) GetQuoteItems(ctx context.Context, queryTargets []string) ([]QuoteItem, error) {
+	consensus, err := median.GetConsensus(ctx, queryTargets)
+	var partial *PartialError
+	if err != nil && !errors.As(err, &partial) {
+		return nil, err
+	}
+
+	var quoteItems []QuoteItem
+	for _, c := range consensus {
+		quoteItems = append(quoteItems, c.QuoteItem)
+	}
+
+	sortQuoteItemsAlphabeticallyASC(quoteItems)
+
+	return quoteItems, err
+}
+
+func (median Median) GetConsensus(ctx context.Context, queryTargets []string) ([]Consensus, error) {
+	results := make([]providerResult, len(median.Providers))
+
+	var wg sync.WaitGroup
+	for i, provider := range median.Providers {
+		wg.Add(1)
+		go func(i int, provider Provider) {
+			defer wg.Done()
+			providerTargets, queryTargetOf := provider.translate(queryTargets)
+			items, err := provider.Oracle.GetQuoteItems(ctx, providerTargets)
+			results[i] = providerResult{items: items, queryTargetOf: queryTargetOf, err: err}
+		}(i, provider)
+	}
+	wg.Wait()
+
+	var keys []string
+	samples := map[string][]medianSample{}
+	var providerErrs []string
+
+	for i, result := range results {
+		provider := median.Providers[i]
+		if result.err != nil {
+			providerErrs = append(providerErrs, fmt.Sprintf("provider=%s %s", provider.Name, result.err.Error()))
+			var partial *PartialError
+			if !errors.As(result.err, &partial) {
+				continue
+			}
+		}
+
+		for _, item := range result.items {
+			target, ok := result.queryTargetOf[strings.ToUpper(item.ID)]
+			if !ok {
+				continue
+			}
+			item.ID = target
+
+			key := target + "/" + item.BaseCurrency
+			if _, ok := samples[key]; !ok {
+				keys = append(keys, key)
+			}
+			samples[key] = append(samples[key], medianSample{source: provider.Name, item: item})
+		}
+	}
+
+	found := map[string]bool{}
+	var out []Consensus
+	for _, key := range keys {
+		c := median.consensus(samples[key])
+		found[c.QuoteItem.ID] = true
+		out = append(out, c)
+	}
+
+	var failed []TargetError
+	for _, target := range queryTargets {
+		if !found[target] {
+			failed = append(failed, TargetError{
+				Target: target,
+				Err:    fmt.Errorf("no provider returned quotes: %s", strings.Join(providerErrs, "; ")),
+			})
+		}
+	}
+	if len(failed) > 0 {
+		return out, &PartialError{Failed: failed}
+	}
+
+	return out, nil
+}
+
+func (median Median) consensus(samples []medianSample) Consensus {
+	mid := medianPrice(samples)
+
+	var kept []medianSample
+	var excluded []string
+	for _, sample := range samples {
+		if median.MaxDeviationPercent > 0 && deviationPercent(sample.item.Price, mid) > median.MaxDeviationPercent {
+			excluded = append(excluded, sample.source)
+			continue
+		}
+		kept = append(kept, sample)
+	}
+	if len(kept) == 0 {
+		kept, excluded = samples, nil
+	}
+	mid = medianPrice(kept)
+
+	item := kept[0].item
+	item.Price = mid
+
+	var sources []string
+	low, high := kept[0].item.Price, kept[0].item.Price
+	for _, sample := range kept {
+		sources = append(sources, sample.source)
+		low = decimal.Min(low, sample.item.Price)
+		high = decimal.Max(high, sample.item.Price)
+		if sample.item.LastUpdated.After(item.LastUpdated) {
+			item.LastUpdated = sample.item.LastUpdated
+		}
+	}
+
+	c := Consensus{
+		Sources:  sources,
+		Excluded: excluded,
+	}
+	if !mid.IsZero() {
+		c.SpreadPercent = high.Sub(low).Div(mid).Mul(decimal.NewFromInt(100)).InexactFloat64()
+	}
+
+	item.Source = fmt.Sprintf("median(%s) spread=%.2f%%", strings.Join(sources, ","), c.SpreadPercent)
+	if len(excluded) > 0 {
+		item.Source += fmt.Sprintf(" excluded=%s", strings.Join(excluded, ","))
+	}
+	c.QuoteItem = item
+
+	return c
+}
+
+func medianPrice(samples []medianSample) decimal.Decimal {
+	prices := make([]decimal.Decimal, len(samples))
+	for i, sample := range samples {
+		prices[i] = sample.item.Price
+	}
+	sort.Slice(prices, func(i, j int) bool {
+		return prices[i].LessThan(prices[j])
+	})
+
+	n := len(prices)
+	if n%2 == 1 {
+		return prices[n/2]
+	}
+	return prices[n/2-1].Add(prices[n/2]).Div(decimal.NewFromInt(2))
+}
+
+func deviationPercent(price, reference decimal.Decimal) float64 {
+	if reference.IsZero() {
+		return 0
+	}
+	return price.Sub(reference).Abs().Div(reference).Mul(decimal.NewFromInt(100)).InexactFloat64()
+}
@@ -0,0 +1,71 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMedianGetConsensus(t *testing.T) {
+	updated := time.UnixMilli(2)
+	median := Median{
+		MaxDeviationPercent: 5,
+		Providers: []Provider{
+			{Name: "a", Oracle: stubOracle{items: map[string]QuoteItem{"BTC": {Symbol: "BTC", BaseCurrency: "USD", Price: decimal.NewFromInt(60000), LastUpdated: time.UnixMilli(1)}}}},
+			{Name: "b", Oracle: stubOracle{items: map[string]QuoteItem{"BTC": {Symbol: "BTC", BaseCurrency: "USD", Price: decimal.NewFromInt(60600), LastUpdated: updated}}}},
+			{Name: "c", Oracle: stubOracle{items: map[string]QuoteItem{"BTC": {Symbol: "BTC", BaseCurrency: "USD", Price: decimal.NewFromInt(30000)}}}},
+			{Name: "d", Oracle: stubOracle{err: errors.New("request returns statusCode=429")}},
+		},
+	}
+
+	consensus, err := median.GetConsensus(context.Background(), []string{"BTC"})
+	if err != nil {
+		t.FailNow()
+	}
+
+	assert.Len(t, consensus, 1)
+	assert.Equal(t, "60300", consensus[0].QuoteItem.Price.String())
+	assert.Equal(t, updated, consensus[0].QuoteItem.LastUpdated)
+	assert.Equal(t, []string{"a", "b"}, consensus[0].Sources)
+	assert.Equal(t, []string{"c"}, consensus[0].Excluded)
+	assert.InDelta(t, 0.995, consensus[0].SpreadPercent, 0.001)
+	assert.Equal(t, "median(a,b) spread=1.00% excluded=c", consensus[0].QuoteItem.Source)
+}
+
+func TestMedianGetQuoteItemsMissing(t *testing.T) {
+	median := Median{Providers: []Provider{
+		{Name: "a", Oracle: stubOracle{err: errors.New("boom")}},
+	}}
+
+	_, err := median.GetQuoteItems(context.Background(), []string{"BTC"})
+	assert.EqualError(t, err, "1 targets failed: target=BTC no provider returned quotes: provider=a boom")
+}
+
+func TestMedianGetQuoteItemsKeepsFoundTargets(t *testing.T) {
+	median := Median{Providers: []Provider{
+		{Name: "a", Oracle: stubOracle{items: map[string]QuoteItem{"BTC": {Symbol: "BTC", BaseCurrency: "USD", Price: decimal.NewFromInt(60000)}}}},
+		{Name: "b", Oracle: stubOracle{items: map[string]QuoteItem{"BTC": {Symbol: "BTC", BaseCurrency: "USD", Price: decimal.NewFromInt(60200)}}}},
+	}}
+
+	quoteItems, err := median.GetQuoteItems(context.Background(), []string{"BTC", "ETH"})
+
+	var partial *PartialError
+	assert.True(t, errors.As(err, &partial))
+	assert.Equal(t, []string{"ETH"}, partial.Targets())
+	assert.Len(t, quoteItems, 1)
+	assert.Equal(t, "60100", quoteItems[0].Price.String())
+}
+
+func TestMedianPrice(t *testing.T) {
+	samples := []medianSample{
+		{item: QuoteItem{Price: decimal.NewFromInt(3)}},
+		{item: QuoteItem{Price: decimal.NewFromInt(1)}},
+		{item: QuoteItem{Price: decimal.NewFromInt(2)}},
+	}
+	assert.Equal(t, "2", medianPrice(samples).String())
+	assert.Equal(t, "1.5", medianPrice(samples[1:]).String())
+}
@@ -2,9 +2,15 @@ package oracle
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
 	"net/url"
 	"sort"
+	"strings"
 	"time"
+
+	"github.com/shopspring/decimal"
 )
 
 const defaultFiat = "USD"
@@ -14,11 +20,29 @@ type Oracle interface {
 }
 
 type QuoteItem struct {
+	ID           string
 	Symbol       string
 	Name         string
 	LastUpdated  time.Time
 	BaseCurrency string
-	Price        float32
+	Price        decimal.Decimal
+	Bid          *decimal.Decimal
+	Ask          *decimal.Decimal
+	Source       string
+
+	ChangePercent24h *decimal.Decimal
+	Volume24h        *decimal.Decimal
+	MarketCap        *decimal.Decimal
+	High24h          *decimal.Decimal
+	Low24h           *decimal.Decimal
+}
+
+type Provider struct {
+	Name   string
+	Oracle Oracle
+	// Targets maps the composite oracle query targets to this provider's identifiers.
+	// Targets missing from the map are passed through unchanged.
+	Targets map[string]string
 }
 
 type query struct {
@@ -42,7 +66,128 @@ func buildURLWithQueryParams(baseURL string, queries []query) (string, error) {
 }
 
 func sortQuoteItemsAlphabeticallyASC(quoteItems []QuoteItem) {
-	sort.Slice(quoteItems, func(i, j int) bool {
-		return quoteItems[i].Symbol < quoteItems[j].Symbol
+	sort.SliceStable(quoteItems, func(i, j int) bool {
+		if quoteItems[i].Symbol != quoteItems[j].Symbol {
+			return quoteItems[i].Symbol < quoteItems[j].Symbol
+		}
+		return quoteItems[i].BaseCurrency < quoteItems[j].BaseCurrency
 	})
 }
+
+func quoteCurrenciesOrDefault(currencies []string) []string {
+	if len(currencies) == 0 {
+		return []string{defaultFiat}
+	}
+	return currencies
+}
+
+type TargetError struct {
+	Target string
+	Err    error
+}
+
+func (e TargetError) Error() string {
+	return fmt.Sprintf("target=%s %s", e.Target, e.Err.Error())
+}
+
+func (e TargetError) Unwrap() error {
+	return e.Err
+}
+
+// PartialError is returned along with the quote items of the targets that
+// succeeded when an oracle couldn't quote every target.
+type PartialError struct {
+	Failed []TargetError
+}
+
+func (e *PartialError) Error() string {
+	var messages []string
+	for _, failed := range e.Failed {
+		messages = append(messages, failed.Error())
+	}
+	return fmt.Sprintf("%d targets failed: %s", len(e.Failed), strings.Join(messages, "; "))
+}
+
+func (e *PartialError) Targets() []string {
+	var targets []string
+	for _, failed := range e.Failed {
+		targets = append(targets, failed.Target)
+	}
+	return targets
+}
+
+type exchangePair struct {
+	target string
+	base   string
+	quote  string
+}
+
+// exchangePairs expands targets into the markets to read from an exchange.
+// Targets written as BASE/QUOTE name a single market, plain symbols are quoted
+// in each of quoteCurrencies.
+func exchangePairs(targets, quoteCurrencies []string, defaultQuote string) ([]exchangePair, error) {
+	if len(quoteCurrencies) == 0 {
+		quoteCurrencies = []string{defaultQuote}
+	}
+
+	var pairs []exchangePair
+	for _, target := range targets {
+		if strings.Contains(target, "/") {
+			base, quote, err := splitPair(target)
+			if err != nil {
+				return nil, err
+			}
+			pairs = append(pairs, exchangePair{target: target, base: base, quote: quote})
+			continue
+		}
+
+		for _, currency := range quoteCurrencies {
+			pairs = append(pairs, exchangePair{target: target, base: strings.ToUpper(target), quote: strings.ToUpper(currency)})
+		}
+	}
+	return pairs, nil
+}
+
+func (provider Provider) translate(queryTargets []string) ([]string, map[string]string) {
+	var providerTargets []string
+	queryTargetOf := map[string]string{}
+
+	for _, target := range queryTargets {
+		providerTarget, ok := provider.Targets[target]
+		if !ok {
+			providerTarget = target
+		}
+		providerTargets = append(providerTargets, providerTarget)
+		queryTargetOf[strings.ToUpper(providerTarget)] = target
+	}
+
+	return providerTargets, queryTargetOf
+}
+
+func getJSON(ctx context.Context, url string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("request returns statusCode=%d", resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func parsePrice(value string) (*decimal.Decimal, error) {
+	price, err := decimal.NewFromString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid price %q: %w", value, err)
+	}
+	return &price, nil
+}
@@ -1,9 +1,12 @@
 package oracle
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -33,21 +36,21 @@ func TestSortQuoteItemsAlphabeticallyASC(t *testing.T) {
 		Name:         "A Token",
 		LastUpdated:  time.UnixMilli(1),
 		BaseCurrency: "USD",
-		Price:        1,
+		Price:        decimal.NewFromInt(1),
 	}
 	itemB := QuoteItem{
 		Symbol:       "B",
 		Name:         "B Token",
 		LastUpdated:  time.UnixMilli(3),
 		BaseCurrency: "USD",
-		Price:        1,
+		Price:        decimal.NewFromInt(1),
 	}
 	itemC := QuoteItem{
 		Symbol:       "C",
 		Name:         "C Token",
 		LastUpdated:  time.UnixMilli(2),
 		BaseCurrency: "USD",
-		Price:        1,
+		Price:        decimal.NewFromInt(1),
 	}
 
 	quoteItems := []QuoteItem{
@@ -62,3 +65,57 @@ func TestSortQuoteItemsAlphabeticallyASC(t *testing.T) {
 	assert.Equal(t, itemB, quoteItems[1])
 	assert.Equal(t, itemC, quoteItems[2])
 }
+
+func TestSortQuoteItemsByBaseCurrency(t *testing.T) {
+	quoteItems := []QuoteItem{
+		{Symbol: "BTC", BaseCurrency: "USD"},
+		{Symbol: "ADA", BaseCurrency: "USD"},
+		{Symbol: "BTC", BaseCurrency: "THB"},
+	}
+
+	sortQuoteItemsAlphabeticallyASC(quoteItems)
+
+	assert.Equal(t, []QuoteItem{
+		{Symbol: "ADA", BaseCurrency: "USD"},
+		{Symbol: "BTC", BaseCurrency: "THB"},
+		{Symbol: "BTC", BaseCurrency: "USD"},
+	}, quoteItems)
+}
+
+func TestQuoteCurrenciesOrDefault(t *testing.T) {
+	assert.Equal(t, []string{"USD"}, quoteCurrenciesOrDefault(nil))
+	assert.Equal(t, []string{"THB", "EUR"}, quoteCurrenciesOrDefault([]string{"THB", "EUR"}))
+}
+
+func TestExchangePairs(t *testing.T) {
+	pairs, err := exchangePairs([]string{"btc", "ETH/THB"}, []string{"USDT", "busd"}, "USD")
+	if err != nil {
+		t.FailNow()
+	}
+	assert.Equal(t, []exchangePair{
+		{target: "btc", base: "BTC", quote: "USDT"},
+		{target: "btc", base: "BTC", quote: "BUSD"},
+		{target: "ETH/THB", base: "ETH", quote: "THB"},
+	}, pairs)
+
+	pairs, _ = exchangePairs([]string{"BTC"}, nil, "USD")
+	assert.Equal(t, []exchangePair{{target: "BTC", base: "BTC", quote: "USD"}}, pairs)
+
+	_, err = exchangePairs([]string{"BTC/"}, nil, "USD")
+	assert.Error(t, err)
+}
+
+func TestPartialError(t *testing.T) {
+	notFound := errors.New("fund not found")
+	var err error = &PartialError{Failed: []TargetError{
+		{Target: "SCBNK225", Err: notFound},
+		{Target: "KFSDIV", Err: errors.New("request returns statusCode=500")},
+	}}
+
+	assert.EqualError(t, err, "2 targets failed: target=SCBNK225 fund not found; target=KFSDIV request returns statusCode=500")
+	assert.True(t, errors.Is(err.(*PartialError).Failed[0], notFound))
+
+	var partial *PartialError
+	assert.True(t, errors.As(fmt.Errorf("wrapped: %w", err), &partial))
+	assert.Equal(t, []string{"SCBNK225", "KFSDIV"}, partial.Targets())
+}
@@ -0,0 +1,77 @@
+package oracle
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// rateLimiter spaces out calls evenly so that no more than the configured
+// number of requests per second reach an API. A nil limiter never waits.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newRateLimiter(requestsPerSecond float64) *rateLimiter {
+	if requestsPerSecond <= 0 {
+		return nil
+	}
+	return &rateLimiter{interval: time.Duration(float64(time.Second) / requestsPerSecond)}
+}
+
+func (limiter *rateLimiter) Wait(ctx context.Context) error {
+	if limiter == nil {
+		return nil
+	}
+
+	limiter.mu.Lock()
+	current := time.Now()
+	if limiter.next.Before(current) {
+		limiter.next = current
+	}
+	wait := limiter.next.Sub(current)
+	limiter.next = limiter.next.Add(limiter.interval)
+	limiter.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// forEachConcurrently calls fn for every index below n from at most
+// concurrency goroutines and returns once all calls are done.
+func forEachConcurrently(n, concurrency int, fn func(i int)) {
+	if concurrency <= 0 || concurrency > n {
+		concurrency = n
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
@@ -0,0 +1,59 @@
+package oracle
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiterWait(t *testing.T) {
+	limiter := newRateLimiter(50)
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, limiter.Wait(context.Background()))
+	}
+
+	assert.GreaterOrEqual(t, time.Since(start), 60*time.Millisecond)
+}
+
+func TestRateLimiterWaitCanceled(t *testing.T) {
+	limiter := newRateLimiter(0.1)
+	assert.NoError(t, limiter.Wait(context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, limiter.Wait(ctx), context.Canceled)
+
+	var unlimited *rateLimiter
+	assert.NoError(t, unlimited.Wait(context.Background()))
+}
+
+func TestForEachConcurrently(t *testing.T) {
+	var running, maxRunning int32
+	var mu sync.Mutex
+	seen := make([]bool, 10)
+
+	forEachConcurrently(len(seen), 3, func(i int) {
+		current := atomic.AddInt32(&running, 1)
+		mu.Lock()
+		if current > maxRunning {
+			maxRunning = current
+		}
+		seen[i] = true
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+	})
+
+	assert.LessOrEqual(t, maxRunning, int32(3))
+	for _, ok := range seen {
+		assert.True(t, ok)
+	}
+
+	forEachConcurrently(0, 3, func(i int) { t.Fail() })
+}
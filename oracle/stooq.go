@@ -0,0 +1,209 @@
+package oracle
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Stooq reads the latest close of stock and ETF symbols such as aapl.us, quoted
+// in the currency of the market suffix.
+type Stooq struct {
+	// Currencies overrides the currency implied by the market suffix for single
+	// symbols, e.g. LSE lines quoted in pounds rather than pence.
+	Currencies map[string]string
+}
+
+type stooqQuote struct {
+	Symbol string
+	Date   string
+	Time   string
+	Close  string
+}
+
+const stooqQuoteURL = "https://stooq.com/q/l/"
+const stooqSymbolQuery = "s"
+const stooqFieldsQuery = "f"
+const stooqFields = "sd2t2ohlcv"
+const stooqHeaderQuery = "h"
+const stooqFormatQuery = "e"
+const stooqFormat = "csv"
+const stooqNoData = "N/D"
+const stooqTz = "Europe/Warsaw"
+const stooqTimeFormat = "2006-01-02 15:04:05"
+const stooqSource = "stooq"
+
+var stooqMarketCurrencies = map[string]string{
+	"us": "USD",
+	"uk": "GBX",
+	"de": "EUR",
+	"fr": "EUR",
+	"nl": "EUR",
+	"jp": "JPY",
+	"hk": "HKD",
+	"hu": "HUF",
+	"pl": "PLN",
+}
+
+func (stooq Stooq) GetQuoteItems(ctx context.Context, targetSymbols []string) ([]QuoteItem, error) {
+	var quoteItems []QuoteItem
+
+	for _, symbol := range targetSymbols {
+		quoteItem, err := stooq.getQuoteItem(ctx, symbol)
+		if err != nil {
+			return nil, fmt.Errorf("symbol=%s %w", symbol, err)
+		}
+
+		quoteItems = append(quoteItems, *quoteItem)
+	}
+
+	sortQuoteItemsAlphabeticallyASC(quoteItems)
+
+	return quoteItems, nil
+}
+
+func (stooq Stooq) getQuoteItem(ctx context.Context, symbol string) (*QuoteItem, error) {
+	url, err := buildURLWithQueryParams(stooqQuoteURL, []query{
+		{
+			key:   stooqSymbolQuery,
+			value: strings.ToLower(symbol),
+		},
+		{
+			key:   stooqFieldsQuery,
+			value: stooqFields,
+		},
+		{
+			key:   stooqHeaderQuery,
+			value: "",
+		},
+		{
+			key:   stooqFormatQuery,
+			value: stooqFormat,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fail to request quote data from Stooq: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("request returns statusCode=%d", resp.StatusCode)
+	}
+
+	records, err := csv.NewReader(resp.Body).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	quote, err := parseStooqQuote(records)
+	if err != nil {
+		return nil, err
+	}
+
+	currency, err := stooq.currency(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	quoteItem, err := quote.toQuoteItem(currency)
+	if err != nil {
+		return nil, err
+	}
+	quoteItem.ID = symbol
+
+	return quoteItem, nil
+}
+
+func parseStooqQuote(records [][]string) (*stooqQuote, error) {
+	if len(records) < 2 {
+		return nil, fmt.Errorf("unexpected empty quote response")
+	}
+
+	header := records[0]
+	row := records[1]
+	if len(row) != len(header) {
+		return nil, fmt.Errorf("unexpected quote row length=%d", len(row))
+	}
+
+	fields := map[string]string{}
+	for i, name := range header {
+		fields[name] = row[i]
+	}
+
+	quote := &stooqQuote{
+		Symbol: fields["Symbol"],
+		Date:   fields["Date"],
+		Time:   fields["Time"],
+		Close:  fields["Close"],
+	}
+	if quote.Close == "" || quote.Close == stooqNoData {
+		return nil, fmt.Errorf("no quote data available")
+	}
+
+	return quote, nil
+}
+
+func (quote stooqQuote) toQuoteItem(currency string) (*QuoteItem, error) {
+	price, err := decimal.NewFromString(quote.Close)
+	if err != nil {
+		return nil, err
+	}
+
+	timeLoc, err := getTimeLoc(stooqTz)
+	if err != nil {
+		return nil, err
+	}
+
+	lastTrade, err := time.ParseInLocation(stooqTimeFormat, quote.Date+" "+quote.Time, timeLoc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &QuoteItem{
+		Symbol:       strings.ToUpper(quote.Symbol),
+		Name:         strings.ToUpper(quote.Symbol),
+		LastUpdated:  lastTrade,
+		BaseCurrency: currency,
+		Price:        price,
+		Source:       stooqSource,
+	}, nil
+}
+
+func (stooq Stooq) currency(symbol string) (string, error) {
+	for key, currency := range stooq.Currencies {
+		if strings.EqualFold(key, symbol) {
+			return strings.ToUpper(currency), nil
+		}
+	}
+	return getStooqCurrency(symbol)
+}
+
+func getStooqCurrency(symbol string) (string, error) {
+	dot := strings.LastIndex(symbol, ".")
+	if dot == -1 {
+		return "", fmt.Errorf("symbol=%s missing market suffix (e.g. .us)", symbol)
+	}
+
+	currency, ok := stooqMarketCurrencies[strings.ToLower(symbol[dot+1:])]
+	if !ok {
+		return "", fmt.Errorf("symbol=%s unsupported market suffix", symbol)
+	}
+
+	return currency, nil
+}
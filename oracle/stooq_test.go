@@ -0,0 +1,71 @@
+package oracle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseStooqQuote(t *testing.T) {
+	records := [][]string{
+		{"Symbol", "Date", "Time", "Open", "High", "Low", "Close", "Volume"},
+		{"AAPL.US", "2021-11-05", "22:00:09", "151.89", "152.2", "150.06", "151.28", "65414641"},
+	}
+
+	quote, err := parseStooqQuote(records)
+	if err != nil {
+		t.Fail()
+	}
+
+	quoteItem, err := quote.toQuoteItem("USD")
+	if err != nil {
+		t.Fail()
+	}
+
+	warsaw, _ := time.LoadLocation("Europe/Warsaw")
+
+	assert.Equal(t, "AAPL.US", quoteItem.Symbol)
+	assert.Equal(t, "USD", quoteItem.BaseCurrency)
+	assert.Equal(t, "151.28", quoteItem.Price.String())
+	assert.True(t, time.Date(2021, time.November, 5, 22, 0, 9, 0, warsaw).Equal(quoteItem.LastUpdated))
+}
+
+func TestParseStooqQuoteNoData(t *testing.T) {
+	records := [][]string{
+		{"Symbol", "Date", "Time", "Open", "High", "Low", "Close", "Volume"},
+		{"FOO.US", "N/D", "N/D", "N/D", "N/D", "N/D", "N/D", "N/D"},
+	}
+
+	_, err := parseStooqQuote(records)
+	assert.Error(t, err)
+
+	_, err = parseStooqQuote(nil)
+	assert.Error(t, err)
+}
+
+func TestGetStooqCurrency(t *testing.T) {
+	currency, err := getStooqCurrency("vwrl.uk")
+	if err != nil {
+		t.Fail()
+	}
+	assert.Equal(t, "GBX", currency)
+
+	_, err = getStooqCurrency("AAPL")
+	assert.Error(t, err)
+
+	_, err = getStooqCurrency("AAPL.XX")
+	assert.Error(t, err)
+}
+
+func TestStooqCurrencyOverride(t *testing.T) {
+	stooq := Stooq{Currencies: map[string]string{"VUSA.UK": "gbp"}}
+
+	currency, err := stooq.currency("vusa.uk")
+	assert.NoError(t, err)
+	assert.Equal(t, "GBP", currency)
+
+	currency, err = stooq.currency("vwrl.uk")
+	assert.NoError(t, err)
+	assert.Equal(t, "GBX", currency)
+}
@@ -0,0 +1,105 @@
+package oracle
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+//go:embed symbols.csv
+var bundledSymbolsCSV []byte
+
+type SymbolRegistry struct {
+	ids map[string]map[string][]string
+}
+
+func NewSymbolRegistry() (*SymbolRegistry, error) {
+	registry := &SymbolRegistry{ids: map[string]map[string][]string{}}
+	if err := registry.load(bytes.NewReader(bundledSymbolsCSV)); err != nil {
+		return nil, err
+	}
+	return registry, nil
+}
+
+// LoadSymbolRegistry reads a user mapping file on top of the bundled one. A
+// symbol listed for a provider in the file replaces the bundled ids for it.
+func LoadSymbolRegistry(path string) (*SymbolRegistry, error) {
+	registry, err := NewSymbolRegistry()
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("fail to read symbol map: %w", err)
+	}
+	defer f.Close()
+
+	if err := registry.load(f); err != nil {
+		return nil, err
+	}
+	return registry, nil
+}
+
+// Targets maps canonical symbols to the provider's identifiers. Symbols that
+// map to more than one identifier are reported as ambiguous.
+func (registry *SymbolRegistry) Targets(provider string, symbols []string) (map[string]string, error) {
+	out := map[string]string{}
+	if registry == nil {
+		return out, nil
+	}
+	var ambiguous []string
+
+	for _, symbol := range symbols {
+		ids := registry.ids[provider][strings.ToUpper(symbol)]
+		switch len(ids) {
+		case 0:
+		case 1:
+			out[symbol] = ids[0]
+		default:
+			ambiguous = append(ambiguous, fmt.Sprintf("%s (%s)", symbol, strings.Join(ids, "/")))
+		}
+	}
+
+	if len(ambiguous) > 0 {
+		return nil, fmt.Errorf("provider=%s ambiguous symbols %s, map them to a single id in the symbol map", provider, strings.Join(ambiguous, ", "))
+	}
+
+	return out, nil
+}
+
+func (registry *SymbolRegistry) load(r io.Reader) error {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 3
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return fmt.Errorf("fail to parse symbol map: %w", err)
+	}
+
+	loaded := map[string]map[string][]string{}
+	for _, record := range records {
+		symbol, provider, id := strings.ToUpper(strings.TrimSpace(record[0])), strings.TrimSpace(record[1]), strings.TrimSpace(record[2])
+		if loaded[provider] == nil {
+			loaded[provider] = map[string][]string{}
+		}
+		loaded[provider][symbol] = append(loaded[provider][symbol], id)
+	}
+
+	for provider, symbols := range loaded {
+		if registry.ids[provider] == nil {
+			registry.ids[provider] = map[string][]string{}
+		}
+		for symbol, ids := range symbols {
+			registry.ids[provider][symbol] = ids
+		}
+	}
+
+	return nil
+}
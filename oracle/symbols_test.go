@@ -0,0 +1,41 @@
+package oracle
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSymbolRegistryTargets(t *testing.T) {
+	registry, err := NewSymbolRegistry()
+	if err != nil {
+		t.FailNow()
+	}
+
+	targets, err := registry.Targets("coingecko", []string{"BTC", "eth", "NEWCOIN"})
+	if err != nil {
+		t.Fail()
+	}
+	assert.Equal(t, map[string]string{"BTC": "bitcoin", "eth": "ethereum"}, targets)
+
+	targets, err = registry.Targets("coinmarketcap", []string{"BTC"})
+	assert.NoError(t, err)
+	assert.Empty(t, targets)
+}
+
+func TestSymbolRegistryOverrideAndAmbiguous(t *testing.T) {
+	registry, _ := NewSymbolRegistry()
+
+	err := registry.load(strings.NewReader("BTC,coingecko,wrapped-bitcoin\nMAGIC,coingecko,magic\nMAGIC,coingecko,magic-internet-money\n"))
+	if err != nil {
+		t.FailNow()
+	}
+
+	targets, err := registry.Targets("coingecko", []string{"BTC"})
+	assert.NoError(t, err)
+	assert.Equal(t, "wrapped-bitcoin", targets["BTC"])
+
+	_, err = registry.Targets("coingecko", []string{"BTC", "MAGIC"})
+	assert.EqualError(t, err, "provider=coingecko ambiguous symbols MAGIC (magic/magic-internet-money), map them to a single id in the symbol map")
+}
@@ -0,0 +1,193 @@
+package oracle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ThaiGold reads the Gold Traders Association 96.5% gold price announcement,
+// quoted in THB per baht-weight. goldtraders.or.th has no API, so the
+// announcement is read from a third-party JSON mirror of it, by default
+// thaiGoldURL; URL points to another mirror serving the same format.
+type ThaiGold struct {
+	URL string
+}
+
+type thaiGoldResponse struct {
+	Status   string `json:"status"`
+	Response struct {
+		Date       string `json:"date"`
+		UpdateTime string `json:"update_time"`
+		Price      struct {
+			Gold    thaiGoldPrice `json:"gold"`
+			GoldBar thaiGoldPrice `json:"gold_bar"`
+		} `json:"price"`
+	} `json:"response"`
+}
+
+type thaiGoldPrice struct {
+	Buy  string `json:"buy"`
+	Sell string `json:"sell"`
+}
+
+const thaiGoldURL = "https://api.chnwt.dev/thai-gold-api/latest"
+const thaiGoldSource = "goldtraders-mirror"
+const GoldBar = "bar"
+const GoldOrnament = "ornament"
+
+var thaiMonths = map[string]time.Month{
+	"มกราคม":     time.January,
+	"กุมภาพันธ์": time.February,
+	"มีนาคม":     time.March,
+	"เมษายน":     time.April,
+	"พฤษภาคม":    time.May,
+	"มิถุนายน":   time.June,
+	"กรกฎาคม":    time.July,
+	"สิงหาคม":    time.August,
+	"กันยายน":    time.September,
+	"ตุลาคม":     time.October,
+	"พฤศจิกายน":  time.November,
+	"ธันวาคม":    time.December,
+}
+
+var clockPattern = regexp.MustCompile(`(\d{1,2})[:.](\d{2})`)
+
+func (gold ThaiGold) GetQuoteItems(ctx context.Context, targetGoldTypes []string) ([]QuoteItem, error) {
+	url := gold.URL
+	if url == "" {
+		url = thaiGoldURL
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fail to request gold price: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("request returns statusCode=%d", resp.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var jsonRes thaiGoldResponse
+	if err := json.Unmarshal(body, &jsonRes); err != nil {
+		return nil, err
+	}
+
+	return jsonRes.toQuoteItems(targetGoldTypes)
+}
+
+func (res thaiGoldResponse) toQuoteItems(targetGoldTypes []string) ([]QuoteItem, error) {
+	if res.Status != "success" {
+		return nil, fmt.Errorf("gold price response status=%s", res.Status)
+	}
+
+	announced, err := parseThaiDateTime(res.Response.Date, res.Response.UpdateTime)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(targetGoldTypes) == 0 {
+		targetGoldTypes = []string{GoldBar, GoldOrnament}
+	}
+
+	var quoteItems []QuoteItem
+	for _, goldType := range targetGoldTypes {
+		var price thaiGoldPrice
+		switch strings.ToLower(goldType) {
+		case GoldBar:
+			price = res.Response.Price.GoldBar
+		case GoldOrnament:
+			price = res.Response.Price.Gold
+		default:
+			return nil, fmt.Errorf("unknown gold type %s, expected %s or %s", goldType, GoldBar, GoldOrnament)
+		}
+
+		for _, side := range []struct {
+			name  string
+			value string
+		}{{"BUY", price.Buy}, {"SELL", price.Sell}} {
+			value, err := parseThaiGoldPrice(side.value)
+			if err != nil {
+				return nil, err
+			}
+
+			symbol := fmt.Sprintf("GOLD-%s-%s", strings.ToUpper(goldType), side.name)
+			quoteItems = append(quoteItems, QuoteItem{
+				ID:           goldType,
+				Symbol:       symbol,
+				Name:         fmt.Sprintf("Gold 96.5%% %s %s (per baht-weight)", strings.ToLower(goldType), strings.ToLower(side.name)),
+				LastUpdated:  announced,
+				BaseCurrency: thb,
+				Price:        value,
+				Source:       thaiGoldSource,
+			})
+		}
+	}
+
+	sortQuoteItemsAlphabeticallyASC(quoteItems)
+
+	return quoteItems, nil
+}
+
+func parseThaiGoldPrice(value string) (decimal.Decimal, error) {
+	price, err := decimal.NewFromString(strings.ReplaceAll(strings.TrimSpace(value), ",", ""))
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("invalid gold price %q: %w", value, err)
+	}
+	return price, nil
+}
+
+// parseThaiDateTime parses dates like "17 ตุลาคม 2569" (Buddhist era) and a
+// time like "เวลา 09:29 น." in Bangkok time.
+func parseThaiDateTime(date, clock string) (time.Time, error) {
+	fields := strings.Fields(date)
+	if len(fields) != 3 {
+		return time.Time{}, fmt.Errorf("invalid thai date %q", date)
+	}
+
+	day, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid thai date %q", date)
+	}
+	month, ok := thaiMonths[fields[1]]
+	if !ok {
+		return time.Time{}, fmt.Errorf("invalid thai month %q", fields[1])
+	}
+	year, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid thai date %q", date)
+	}
+
+	hour, minute := 0, 0
+	if match := clockPattern.FindStringSubmatch(clock); match != nil {
+		hour, _ = strconv.Atoi(match[1])
+		minute, _ = strconv.Atoi(match[2])
+	}
+
+	timeLoc, err := getTimeLoc(bkkTz)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Date(year-543, month, day, hour, minute, 0, 0, timeLoc), nil
+}
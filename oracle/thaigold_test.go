@@ -0,0 +1,62 @@
+package oracle
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const thaiGoldJSON = `{
+	"status": "success",
+	"response": {
+		"date": "5 พฤศจิกายน 2564",
+		"update_time": "เวลา 09:29 น.",
+		"price": {
+			"gold": {"buy": "28,022.00", "sell": "29,150.00"},
+			"gold_bar": {"buy": "28,500.00", "sell": "28,600.00"}
+		}
+	}
+}`
+
+func TestThaiGoldToQuoteItems(t *testing.T) {
+	var res thaiGoldResponse
+	if err := json.Unmarshal([]byte(thaiGoldJSON), &res); err != nil {
+		t.FailNow()
+	}
+
+	quoteItems, err := res.toQuoteItems([]string{"bar"})
+	if err != nil {
+		t.FailNow()
+	}
+
+	bkk, _ := time.LoadLocation("Asia/Bangkok")
+	announced := time.Date(2021, time.November, 5, 9, 29, 0, 0, bkk)
+
+	assert.Len(t, quoteItems, 2)
+	assert.Equal(t, "GOLD-BAR-BUY", quoteItems[0].Symbol)
+	assert.Equal(t, "28500", quoteItems[0].Price.String())
+	assert.Equal(t, "THB", quoteItems[0].BaseCurrency)
+	assert.True(t, announced.Equal(quoteItems[0].LastUpdated))
+	assert.Equal(t, "GOLD-BAR-SELL", quoteItems[1].Symbol)
+	assert.Equal(t, "28600", quoteItems[1].Price.String())
+
+	quoteItems, err = res.toQuoteItems(nil)
+	assert.NoError(t, err)
+	assert.Len(t, quoteItems, 4)
+
+	_, err = res.toQuoteItems([]string{"silver"})
+	assert.Error(t, err)
+}
+
+func TestParseThaiDateTime(t *testing.T) {
+	bkk, _ := time.LoadLocation("Asia/Bangkok")
+
+	result, err := parseThaiDateTime("17 ตุลาคม 2569", "")
+	assert.NoError(t, err)
+	assert.True(t, time.Date(2026, time.October, 17, 0, 0, 0, 0, bkk).Equal(result))
+
+	_, err = parseThaiDateTime("17 October 2026", "")
+	assert.Error(t, err)
+}
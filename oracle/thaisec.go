@@ -4,64 +4,153 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strings"
 	"time"
+
+	"github.com/shopspring/decimal"
 )
 
 type ThaiSec struct {
 	FundFactAPIKey      string
 	FundDailyInfoAPIKey string
+	NavLookbackDays     int
+	Calendar            *Calendar
+	Concurrency         int
+	RequestsPerSecond   float64
+	FundCachePath       string
+	FundCacheTTL        time.Duration
+
+	fundFactLimiter      *rateLimiter
+	fundDailyInfoLimiter *rateLimiter
+	fundCache            *fundInfoCache
 }
 
 type fundInfo struct {
 	ProjectID       string `json:"proj_id"`
 	ProjectABBRName string `json:"proj_abbr_name"`
+	ClassABBRName   string `json:"class_abbr_name"`
+	ProjectNameEN   string `json:"proj_name_en"`
+	AMCID           string `json:"unique_id"`
+	RegisterDate    string `json:"regis_date"`
 }
 
 type fundPriceInfo struct {
-	NavDate string  `json:"nav_date"`
-	LastVal float32 `json:"last_val"`
+	NavDate       string          `json:"nav_date"`
+	ClassABBRName string          `json:"class_abbr_name"`
+	LastVal       decimal.Decimal `json:"last_val"`
 }
 
 const thb = "THB"
 const bkkTz = "Asia/Bangkok"
-const navDateOffSet = 10
+const defaultNavLookbackDays = 10
+const defaultFundConcurrency = 4
+const defaultRequestsPerSecond = 5
 
 const fundInfoURL = "https://api.sec.or.th/FundFactsheet/fund/class_fund"
 const fundPriceURLTemplate = "https://api.sec.or.th/FundDailyInfo/%s/dailynav/%s"
 const apiKeyHeader = "Ocp-Apim-Subscription-Key"
 const navDateFormat = "2006-01-02"
+const thaiSecSource = "thaisec"
 
-var now = time.Now()
+var now = time.Now
 
+var errNavNotFound = errors.New("NAV not published")
+var errFundNotFound = errors.New("fund not found")
+var errFundAmbiguous = errors.New("ambiguous fund")
+
+// GetQuoteItems carries on past funds that fail and returns the quotes it got
+// together with a *PartialError listing the failed funds.
 func (sec ThaiSec) GetQuoteItems(ctx context.Context, targetFundNames []string) ([]QuoteItem, error) {
-	var quoteItems []QuoteItem
+	sec = sec.withRateLimits()
+	if sec.FundCachePath != "" {
+		sec.fundCache = loadFundInfoCache(sec.FundCachePath, sec.FundCacheTTL)
+	}
 
-	for _, fundName := range targetFundNames {
-		quoteItem, err := sec.getQuoteItem(ctx, fundName, getQueryNavDate(navDateOffSet))
-		if err != nil {
-			return nil, fmt.Errorf("fundName=%s %w", fundName, err)
-		}
+	results := make([]*QuoteItem, len(targetFundNames))
+	failed := sec.forEachFund(targetFundNames, func(i int) error {
+		var err error
+		results[i], err = sec.getQuoteItem(ctx, targetFundNames[i])
+		return err
+	})
 
-		quoteItems = append(quoteItems, *quoteItem)
+	var quoteItems []QuoteItem
+	for _, result := range results {
+		if result != nil {
+			quoteItems = append(quoteItems, *result)
+		}
 	}
 
 	sortQuoteItemsAlphabeticallyASC(quoteItems)
 
+	_ = sec.fundCache.save()
+
+	if len(failed) > 0 {
+		return quoteItems, &PartialError{Failed: failed}
+	}
+
 	return quoteItems, nil
 }
 
-func (sec ThaiSec) getQuoteItem(ctx context.Context, fundName, queryNavDate string) (*QuoteItem, error) {
-	fundInfo, err := sec.getFundInfo(ctx, fundName)
+// forEachFund runs fn for each fund on the worker pool and returns the funds it
+// failed for.
+func (sec ThaiSec) forEachFund(funds []string, fn func(i int) error) []TargetError {
+	concurrency := sec.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultFundConcurrency
+	}
+
+	errs := make([]error, len(funds))
+	forEachConcurrently(len(funds), concurrency, func(i int) {
+		errs[i] = fn(i)
+	})
+
+	var failed []TargetError
+	for i, fund := range funds {
+		if errs[i] != nil {
+			failed = append(failed, TargetError{Target: fund, Err: errs[i]})
+		}
+	}
+	return failed
+}
+
+// withRateLimits shares one limiter per API key between the workers of a run,
+// as each key is rate limited separately.
+func (sec ThaiSec) withRateLimits() ThaiSec {
+	requestsPerSecond := sec.RequestsPerSecond
+	if requestsPerSecond <= 0 {
+		requestsPerSecond = defaultRequestsPerSecond
+	}
+
+	sec.fundFactLimiter = newRateLimiter(requestsPerSecond)
+	sec.fundDailyInfoLimiter = newRateLimiter(requestsPerSecond)
+	return sec
+}
+
+func (sec ThaiSec) NewNAVExpected() (bool, string, error) {
+	timeLoc, err := getTimeLoc(bkkTz)
 	if err != nil {
-		return nil, fmt.Errorf("fail to get fund info from Thai SEC API: %w", err)
+		return false, "", err
+	}
+
+	today := now().In(timeLoc)
+	if sec.Calendar.IsTradingDay(today) {
+		return true, "", nil
 	}
+	if holiday, ok := sec.Calendar.Holiday(today); ok {
+		return false, holiday, nil
+	}
+
+	return false, "weekend", nil
+}
 
-	fundPrice, err := sec.getFundPrice(ctx, fundInfo.ProjectID, queryNavDate)
+func (sec ThaiSec) getQuoteItem(ctx context.Context, fundName string) (*QuoteItem, error) {
+	fundInfo, err := sec.lookupFundInfo(ctx, fundName)
 	if err != nil {
-		return nil, fmt.Errorf("fundID=%s queryNavDate=%s fail to request fund price from Thai SEC API: %w", fundInfo.ProjectID, queryNavDate, err)
+		return nil, fmt.Errorf("fail to get fund info from Thai SEC API: %w", err)
 	}
 
 	timeLoc, err := getTimeLoc(bkkTz)
@@ -69,20 +158,47 @@ func (sec ThaiSec) getQuoteItem(ctx context.Context, fundName, queryNavDate stri
 		return nil, err
 	}
 
+	fundPrice, err := sec.getLatestFundPrice(ctx, *fundInfo, timeLoc)
+	if err != nil {
+		return nil, err
+	}
+
 	parsedTime, err := time.ParseInLocation(navDateFormat, fundPrice.NavDate, timeLoc)
 	if err != nil {
 		return nil, err
 	}
 
 	return &QuoteItem{
+		ID:           fundName,
 		Symbol:       fundName,
-		Name:         fundInfo.ProjectABBRName,
+		Name:         fundInfo.name(),
 		LastUpdated:  parsedTime,
 		BaseCurrency: thb,
 		Price:        fundPrice.LastVal,
+		Source:       thaiSecSource,
 	}, nil
 }
 
+// lookupFundInfo prefers a cached project within the TTL and falls back to an
+// expired one when the FundFactsheet API fails.
+func (sec ThaiSec) lookupFundInfo(ctx context.Context, fundName string) (*fundInfo, error) {
+	cached, found, fresh := sec.fundCache.get(fundName)
+	if fresh {
+		return cached, nil
+	}
+
+	info, err := sec.getFundInfo(ctx, fundName)
+	if err != nil {
+		if found && !errors.Is(err, errFundNotFound) && !errors.Is(err, errFundAmbiguous) {
+			return cached, nil
+		}
+		return nil, err
+	}
+
+	sec.fundCache.put(fundName, *info)
+	return info, nil
+}
+
 func (sec ThaiSec) getFundInfo(ctx context.Context, fundName string) (*fundInfo, error) {
 	reqBody, err := json.Marshal(map[string]string{
 		"name": fundName,
@@ -91,7 +207,11 @@ func (sec ThaiSec) getFundInfo(ctx context.Context, fundName string) (*fundInfo,
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", fundInfoURL, bytes.NewBuffer(reqBody))
+	if err := sec.fundFactLimiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", fundInfoURL, bytes.NewBuffer(reqBody))
 	if err != nil {
 		return nil, err
 	}
@@ -103,26 +223,108 @@ func (sec ThaiSec) getFundInfo(ctx context.Context, fundName string) (*fundInfo,
 	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNoContent {
+		return selectFundClass(fundName, nil)
+	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("request returns statusCode=%d", resp.StatusCode)
 	}
 
-	defer resp.Body.Close()
 	respBody, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
 	}
 
 	var jsonRes []fundInfo
-	if err := json.Unmarshal(respBody, &jsonRes); err != nil {
-		return nil, err
+	if len(bytes.TrimSpace(respBody)) > 0 {
+		if err := json.Unmarshal(respBody, &jsonRes); err != nil {
+			return nil, err
+		}
+	}
+
+	return selectFundClass(fundName, jsonRes)
+}
+
+// selectFundClass picks the share class whose abbreviation matches fundName,
+// or the only class of a project whose abbreviation matches it.
+func selectFundClass(fundName string, candidates []fundInfo) (*fundInfo, error) {
+	var classMatches, projectMatches []fundInfo
+	for _, candidate := range candidates {
+		if strings.EqualFold(candidate.ClassABBRName, fundName) {
+			classMatches = append(classMatches, candidate)
+		}
+		if strings.EqualFold(candidate.ProjectABBRName, fundName) {
+			projectMatches = append(projectMatches, candidate)
+		}
+	}
+
+	switch {
+	case len(classMatches) == 1:
+		return &classMatches[0], nil
+	case len(classMatches) > 1:
+		return nil, fmt.Errorf("%w %s, candidates: %s", errFundAmbiguous, fundName, fundNames(classMatches))
+	case len(projectMatches) == 1:
+		return &projectMatches[0], nil
+	case len(projectMatches) > 1:
+		return nil, fmt.Errorf("%w %s has several classes, use one of: %s", errFundAmbiguous, fundName, fundNames(projectMatches))
+	case len(candidates) > 0:
+		return nil, fmt.Errorf("%w %s, candidates: %s", errFundNotFound, fundName, fundNames(candidates))
 	}
 
-	return &jsonRes[0], nil
+	return nil, fmt.Errorf("%w %s", errFundNotFound, fundName)
 }
 
-func (sec ThaiSec) getFundPrice(ctx context.Context, fundID, queryNavDate string) (*fundPriceInfo, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf(fundPriceURLTemplate, fundID, queryNavDate), nil)
+func (info fundInfo) name() string {
+	if info.ClassABBRName != "" {
+		return info.ClassABBRName
+	}
+	return info.ProjectABBRName
+}
+
+func fundNames(infos []fundInfo) string {
+	var names []string
+	for _, info := range infos {
+		names = append(names, info.name())
+	}
+	return strings.Join(names, ", ")
+}
+
+func (sec ThaiSec) getLatestFundPrice(ctx context.Context, fund fundInfo, timeLoc *time.Location) (*fundPriceInfo, error) {
+	lookbackDays := sec.NavLookbackDays
+	if lookbackDays <= 0 {
+		lookbackDays = defaultNavLookbackDays
+	}
+
+	navDate := now().In(timeLoc)
+	if !sec.Calendar.IsTradingDay(navDate) {
+		navDate = sec.Calendar.PreviousTradingDay(navDate)
+	}
+
+	for i := 0; i < lookbackDays; i++ {
+		queryNavDate := navDate.Format(navDateFormat)
+
+		fundPrice, err := sec.getFundPrice(ctx, fund, queryNavDate)
+		if err == nil {
+			return fundPrice, nil
+		}
+		if !errors.Is(err, errNavNotFound) {
+			return nil, fmt.Errorf("fundID=%s queryNavDate=%s fail to request fund price from Thai SEC API: %w", fund.ProjectID, queryNavDate, err)
+		}
+
+		navDate = sec.Calendar.PreviousTradingDay(navDate)
+	}
+
+	return nil, fmt.Errorf("fundID=%s no NAV published within the last %d business days", fund.ProjectID, lookbackDays)
+}
+
+func (sec ThaiSec) getFundPrice(ctx context.Context, fund fundInfo, queryNavDate string) (*fundPriceInfo, error) {
+	if err := sec.fundDailyInfoLimiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf(fundPriceURLTemplate, fund.ProjectID, queryNavDate), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -134,26 +336,65 @@ func (sec ThaiSec) getFundPrice(ctx context.Context, fundID, queryNavDate string
 		return nil, err
 	}
 
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNoContent {
+		return nil, errNavNotFound
+	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("request returns statusCode=%d", resp.StatusCode)
 	}
 
-	defer resp.Body.Close()
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
 	}
 
-	var jsonRes fundPriceInfo
-	if err := json.Unmarshal(body, &jsonRes); err != nil {
+	return parseFundPrice(body, fund.ClassABBRName)
+}
+
+// parseFundPrice reads a daily NAV response, which lists one NAV per class for
+// multi-class funds.
+func parseFundPrice(body []byte, className string) (*fundPriceInfo, error) {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 {
+		return nil, errNavNotFound
+	}
+
+	var jsonRes []fundPriceInfo
+	if body[0] != '[' {
+		// A single object still has to be the requested class, unless the
+		// fund has no classes
+		var price fundPriceInfo
+		if err := json.Unmarshal(body, &price); err != nil {
+			return nil, err
+		}
+		if price.ClassABBRName == "" {
+			return &price, nil
+		}
+		jsonRes = append(jsonRes, price)
+	} else if err := json.Unmarshal(body, &jsonRes); err != nil {
 		return nil, err
 	}
 
-	return &jsonRes, nil
-}
+	var classNames []string
+	for i, price := range jsonRes {
+		if className != "" && strings.EqualFold(price.ClassABBRName, className) {
+			return &jsonRes[i], nil
+		}
+		classNames = append(classNames, price.ClassABBRName)
+	}
+
+	switch {
+	case len(jsonRes) == 0:
+		return nil, errNavNotFound
+	case len(jsonRes) == 1 && className == "":
+		return &jsonRes[0], nil
+	case className == "":
+		return nil, fmt.Errorf("%w, NAV published for classes: %s", errFundAmbiguous, strings.Join(classNames, ", "))
+	}
 
-func getQueryNavDate(pastDayOffset int) string {
-	return now.AddDate(0, 0, -1*pastDayOffset).Format(navDateFormat)
+	return nil, fmt.Errorf("no NAV for class %s, NAV published for classes: %s", className, strings.Join(classNames, ", "))
 }
 
 func getTimeLoc(countryTz string) (*time.Location, error) {
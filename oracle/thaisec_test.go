@@ -7,12 +7,27 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func TestGetQueryNavDate(t *testing.T) {
-	now = time.Date(2021, time.October, 31, 0, 0, 0, 0, time.UTC)
+func TestNewNAVExpected(t *testing.T) {
+	calendar, _ := NewThaiCalendar()
+	sec := ThaiSec{Calendar: calendar}
+	t.Cleanup(func() { now = time.Now })
 
-	assert.Equal(t, "2021-10-31", getQueryNavDate(0))
-	assert.Equal(t, "2021-10-30", getQueryNavDate(1))
-	assert.Equal(t, "2021-09-30", getQueryNavDate(31))
+	now = func() time.Time { return time.Date(2026, time.October, 13, 10, 0, 0, 0, time.UTC) }
+	expected, reason, err := sec.NewNAVExpected()
+	if err != nil {
+		t.Fail()
+	}
+	assert.False(t, expected)
+	assert.Equal(t, "H.M. King Bhumibol Adulyadej The Great Memorial Day", reason)
+
+	now = func() time.Time { return time.Date(2026, time.October, 17, 10, 0, 0, 0, time.UTC) }
+	expected, reason, _ = sec.NewNAVExpected()
+	assert.False(t, expected)
+	assert.Equal(t, "weekend", reason)
+
+	now = func() time.Time { return time.Date(2026, time.October, 14, 10, 0, 0, 0, time.UTC) }
+	expected, _, _ = sec.NewNAVExpected()
+	assert.True(t, expected)
 }
 
 func TestGetTimeLoc(t *testing.T) {
@@ -34,3 +49,65 @@ func TestGetTimeLoc(t *testing.T) {
 
 	assert.Equal(t, utc, result)
 }
+
+func TestSelectFundClass(t *testing.T) {
+	candidates := []fundInfo{
+		{ProjectID: "M0001", ProjectABBRName: "K-USA", ClassABBRName: "K-USA-A(A)"},
+		{ProjectID: "M0001", ProjectABBRName: "K-USA", ClassABBRName: "K-USA-A(D)"},
+		{ProjectID: "M0002", ProjectABBRName: "K-USXNDQ", ClassABBRName: ""},
+	}
+
+	info, err := selectFundClass("k-usa-a(d)", candidates)
+	assert.NoError(t, err)
+	assert.Equal(t, "K-USA-A(D)", info.ClassABBRName)
+
+	info, err = selectFundClass("K-USXNDQ", candidates)
+	assert.NoError(t, err)
+	assert.Equal(t, "M0002", info.ProjectID)
+
+	_, err = selectFundClass("K-USA", candidates)
+	assert.ErrorIs(t, err, errFundAmbiguous)
+	assert.EqualError(t, err, "ambiguous fund K-USA has several classes, use one of: K-USA-A(A), K-USA-A(D)")
+
+	_, err = selectFundClass("K-US", candidates)
+	assert.ErrorIs(t, err, errFundNotFound)
+	assert.EqualError(t, err, "fund not found K-US, candidates: K-USA-A(A), K-USA-A(D), K-USXNDQ")
+
+	_, err = selectFundClass("SCBNK225", nil)
+	assert.EqualError(t, err, "fund not found SCBNK225")
+}
+
+func TestParseFundPrice(t *testing.T) {
+	price, err := parseFundPrice([]byte(`{"nav_date":"2021-11-05","last_val":12.3456}`), "")
+	assert.NoError(t, err)
+	assert.Equal(t, "12.3456", price.LastVal.String())
+
+	single := []byte(`{"nav_date":"2021-11-05","class_abbr_name":"K-USA-A(A)","last_val":10.1}`)
+	price, err = parseFundPrice(single, "k-usa-a(a)")
+	assert.NoError(t, err)
+	assert.Equal(t, "10.1", price.LastVal.String())
+
+	_, err = parseFundPrice(single, "K-USA-A(D)")
+	assert.EqualError(t, err, "no NAV for class K-USA-A(D), NAV published for classes: K-USA-A(A)")
+
+	price, err = parseFundPrice([]byte(`{"nav_date":"2021-11-05","class_abbr_name":"","last_val":12.3456}`), "SCBNK225")
+	assert.NoError(t, err)
+	assert.Equal(t, "12.3456", price.LastVal.String())
+
+	classes := []byte(`[{"nav_date":"2021-11-05","class_abbr_name":"K-USA-A(A)","last_val":10.1},{"nav_date":"2021-11-05","class_abbr_name":"K-USA-A(D)","last_val":9.87}]`)
+	price, err = parseFundPrice(classes, "K-USA-A(D)")
+	assert.NoError(t, err)
+	assert.Equal(t, "9.87", price.LastVal.String())
+
+	_, err = parseFundPrice(classes, "")
+	assert.ErrorIs(t, err, errFundAmbiguous)
+
+	_, err = parseFundPrice(classes, "K-USA-A(R)")
+	assert.EqualError(t, err, "no NAV for class K-USA-A(R), NAV published for classes: K-USA-A(A), K-USA-A(D)")
+
+	_, err = parseFundPrice([]byte(" "), "")
+	assert.ErrorIs(t, err, errNavNotFound)
+
+	_, err = parseFundPrice([]byte("[]"), "")
+	assert.ErrorIs(t, err, errNavNotFound)
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/koromo-wd/priceupdater/config"
+	"github.com/koromo-wd/priceupdater/oracle"
+	"github.com/robfig/cron/v3"
+)
+
+func serve(cfg *config.Config, registry *oracle.SymbolRegistry) error {
+	shutdownCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	logger := cron.PrintfLogger(log.Default())
+	scheduler := cron.New(
+		cron.WithLogger(logger),
+		cron.WithChain(cron.Recover(logger), cron.SkipIfStillRunning(logger)),
+	)
+
+	for _, job := range cfg.Jobs {
+		if job.Schedule == "" {
+			return fmt.Errorf("job=%s missing schedule", job.Name)
+		}
+
+		runner, err := newJobRunner(job, registry)
+		if err != nil {
+			return fmt.Errorf("job=%s %w", job.Name, err)
+		}
+
+		timeout := job.Timeout
+		if timeout <= 0 {
+			timeout, err = scheduleInterval(job.Schedule)
+			if err != nil {
+				return fmt.Errorf("job=%s invalid schedule %q: %w", job.Name, job.Schedule, err)
+			}
+		}
+
+		jitter := newJitter(job.Jitter)
+		if _, err := scheduler.AddFunc(job.Schedule, func() {
+			runner.runScheduled(shutdownCtx, jitter, timeout)
+		}); err != nil {
+			return fmt.Errorf("job=%s invalid schedule %q: %w", job.Name, job.Schedule, err)
+		}
+
+		log.Printf("Scheduled job %s: %s, timeout %s", job.Name, job.Schedule, timeout)
+	}
+
+	scheduler.Start()
+	<-shutdownCtx.Done()
+
+	log.Print("Shutting down, cancelling running jobs")
+	<-scheduler.Stop().Done()
+
+	return nil
+}
+
+// runScheduled waits out the jitter, giving up if shutdown starts meanwhile. A
+// run is cancelled on shutdown or once it takes longer than timeout, so a hung
+// request doesn't block the following runs.
+func (runner jobRunner) runScheduled(shutdownCtx context.Context, jitter func() time.Duration, timeout time.Duration) {
+	select {
+	case <-time.After(jitter()):
+	case <-shutdownCtx.Done():
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(shutdownCtx, timeout)
+	defer cancel()
+
+	log.Printf("Running job %s", runner.job.Name)
+	if err := runner.run(ctx); err != nil {
+		log.Printf("Job %s failed: %s", runner.job.Name, err.Error())
+		return
+	}
+	log.Printf("Finish job %s", runner.job.Name)
+}
+
+// newJitter returns a random delay source. Each job gets its own since runs of
+// the same job never overlap.
+func newJitter(max time.Duration) func() time.Duration {
+	if max <= 0 {
+		return func() time.Duration { return 0 }
+	}
+
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	return func() time.Duration {
+		return time.Duration(r.Int63n(int64(max)))
+	}
+}
+
+// scheduleInterval returns the time between the next two runs of a schedule.
+func scheduleInterval(schedule string) (time.Duration, error) {
+	parsed, err := cron.ParseStandard(schedule)
+	if err != nil {
+		return 0, err
+	}
+
+	next := parsed.Next(time.Now())
+	return parsed.Next(next).Sub(next), nil
+}
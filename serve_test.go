@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/koromo-wd/priceupdater/oracle"
+	"github.com/koromo-wd/priceupdater/updater"
+	"github.com/stretchr/testify/assert"
+)
+
+type blockingOracle struct{}
+
+func (blockingOracle) GetQuoteItems(ctx context.Context, queryTargets []string) ([]oracle.QuoteItem, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestScheduleInterval(t *testing.T) {
+	interval, err := scheduleInterval("*/5 * * * *")
+	assert.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, interval)
+
+	interval, err = scheduleInterval("@every 90s")
+	assert.NoError(t, err)
+	assert.Equal(t, 90*time.Second, interval)
+
+	_, err = scheduleInterval("every day")
+	assert.Error(t, err)
+}
+
+func TestRunScheduledCancelsHungRun(t *testing.T) {
+	var written []updater.TradingPair
+	runner := jobRunner{oracle: blockingOracle{}, updater: recordingUpdater{tradingPairs: &written}}
+	noJitter := func() time.Duration { return 0 }
+
+	done := make(chan struct{})
+	go func() {
+		runner.runScheduled(context.Background(), noJitter, 10*time.Millisecond)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("run not cancelled after its timeout")
+	}
+	assert.Nil(t, written)
+
+	shutdownCtx, shutdown := context.WithCancel(context.Background())
+	done = make(chan struct{})
+	go func() {
+		runner.runScheduled(shutdownCtx, noJitter, time.Hour)
+		close(done)
+	}()
+	shutdown()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("run not cancelled on shutdown")
+	}
+}
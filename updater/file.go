@@ -0,0 +1,253 @@
+package updater
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var csvHeaderRow = []string{"pair", "base", "quote", "price", "updated_time", "source"}
+
+type CSVFile struct {
+	Path   string
+	Append bool
+}
+
+type JSONFile struct {
+	Path   string
+	Append bool
+}
+
+type priceRecord struct {
+	Pair        string      `json:"pair"`
+	Base        string      `json:"base"`
+	Quote       string      `json:"quote"`
+	Price       json.Number `json:"price"`
+	UpdatedTime time.Time   `json:"updated_time"`
+	Source      string      `json:"source,omitempty"`
+	Stale       bool        `json:"stale,omitempty"`
+}
+
+func (updater CSVFile) UpdatePrice(ctx context.Context, tradingPairs []TradingPair) error {
+	fresh, stale := splitStale(tradingPairs)
+
+	var rows [][]string
+	for _, pair := range fresh {
+		rows = append(rows, csvRow(pair))
+	}
+
+	if updater.Append {
+		if err := appendCSV(updater.Path, rows); err != nil {
+			return fmt.Errorf("unable to append to csv file: %w", err)
+		}
+		return nil
+	}
+
+	if len(stale) > 0 {
+		staleRows, err := readStaleCSVRows(updater.Path, csvHeaderRow, 0, 5, stale)
+		if err != nil {
+			return fmt.Errorf("unable to read existing csv file: %w", err)
+		}
+		rows = append(rows, staleRows...)
+	}
+
+	if err := writeCSVFile(updater.Path, append([][]string{csvHeaderRow}, rows...)); err != nil {
+		return fmt.Errorf("unable to write csv file: %w", err)
+	}
+
+	return nil
+}
+
+func (updater JSONFile) UpdatePrice(ctx context.Context, tradingPairs []TradingPair) error {
+	fresh, stale := splitStale(tradingPairs)
+
+	var existing []priceRecord
+	if updater.Append || len(stale) > 0 {
+		var err error
+		existing, err = readJSONRecords(updater.Path)
+		if err != nil {
+			return fmt.Errorf("unable to read existing json file: %w", err)
+		}
+	}
+
+	var records []priceRecord
+	if updater.Append {
+		records = existing
+	}
+
+	for _, pair := range fresh {
+		records = append(records, newPriceRecord(pair))
+	}
+
+	if !updater.Append {
+		for _, record := range existing {
+			if stale.has(record.Pair) {
+				record.Stale = true
+				records = append(records, record)
+			}
+		}
+	}
+
+	b, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := writeFileAtomic(updater.Path, append(b, '\n')); err != nil {
+		return fmt.Errorf("unable to write json file: %w", err)
+	}
+
+	return nil
+}
+
+func newPriceRecord(pair TradingPair) priceRecord {
+	return priceRecord{
+		Pair:        pairName(pair),
+		Base:        pair.BaseSymbol,
+		Quote:       pair.QuoteSymbol,
+		Price:       json.Number(pair.Price.String()),
+		UpdatedTime: pair.UpdatedTime,
+		Source:      pair.Source,
+	}
+}
+
+func csvRow(pair TradingPair) []string {
+	return []string{
+		pairName(pair),
+		pair.BaseSymbol,
+		pair.QuoteSymbol,
+		pair.Price.String(),
+		pair.UpdatedTime.Format(time.RFC3339),
+		pair.Source,
+	}
+}
+
+func appendCSV(path string, rows [][]string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	w := csv.NewWriter(f)
+	if info.Size() == 0 {
+		rows = append([][]string{csvHeaderRow}, rows...)
+	}
+	if err := w.WriteAll(rows); err != nil {
+		return err
+	}
+
+	return f.Sync()
+}
+
+func writeCSVFile(path string, rows [][]string) error {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.WriteAll(rows); err != nil {
+		return err
+	}
+
+	return writeFileAtomic(path, buf.Bytes())
+}
+
+// readStaleCSVRows returns the rows of a previously written csv file whose
+// keyColumn is stale, with their markColumn marked as stale.
+func readStaleCSVRows(path string, header []string, keyColumn, markColumn int, stale staleSet) ([][]string, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = len(header)
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var rows [][]string
+	for i, record := range records {
+		if i == 0 || !stale.has(record[keyColumn]) {
+			continue
+		}
+		record[markColumn] = markStale(record[markColumn])
+		rows = append(rows, record)
+	}
+	return rows, nil
+}
+
+func readJSONRecords(path string) ([]priceRecord, error) {
+	var records []priceRecord
+	if err := readJSONFile(path, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// readJSONFile decodes a previously written json file into out, leaving it
+// untouched when the file is missing or empty.
+func readJSONFile(path string, out interface{}) error {
+	b, err := ioutil.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(bytes.TrimSpace(b)) == 0 {
+		return nil
+	}
+	return json.Unmarshal(b, out)
+}
+
+// writeFileAtomic writes to a temporary file in the same directory and renames
+// it over path, so readers never observe a partially written file. An existing
+// file keeps its permissions.
+func writeFileAtomic(path string, data []byte) error {
+	f, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := f.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.Chmod(tmpPath, mode); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, path)
+}
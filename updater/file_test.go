@@ -0,0 +1,126 @@
+package updater
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+var testTradingPairs = []TradingPair{
+	{BaseSymbol: "BTC", QuoteSymbol: "USD", Price: decimal.RequireFromString("104523.12345678"), UpdatedTime: time.Date(2021, time.November, 5, 10, 30, 0, 0, time.UTC), Source: "coingecko"},
+}
+
+func TestCSVFileUpdatePrice(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prices.csv")
+	expectedRow := "BTC/USD,BTC,USD,104523.12345678,2021-11-05T10:30:00Z,coingecko\n"
+
+	assert.NoError(t, CSVFile{Path: path}.UpdatePrice(context.Background(), testTradingPairs))
+	assert.NoError(t, CSVFile{Path: path}.UpdatePrice(context.Background(), testTradingPairs))
+
+	b, _ := ioutil.ReadFile(path)
+	assert.Equal(t, "pair,base,quote,price,updated_time,source\n"+expectedRow, string(b))
+
+	assert.NoError(t, CSVFile{Path: path, Append: true}.UpdatePrice(context.Background(), testTradingPairs))
+
+	b, _ = ioutil.ReadFile(path)
+	assert.Equal(t, "pair,base,quote,price,updated_time,source\n"+expectedRow+expectedRow, string(b))
+}
+
+func TestCSVFileAppendToEmptyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prices.csv")
+	assert.NoError(t, ioutil.WriteFile(path, nil, 0644))
+
+	assert.NoError(t, CSVFile{Path: path, Append: true}.UpdatePrice(context.Background(), testTradingPairs))
+
+	b, _ := ioutil.ReadFile(path)
+	assert.Equal(t, "pair,base,quote,price,updated_time,source\nBTC/USD,BTC,USD,104523.12345678,2021-11-05T10:30:00Z,coingecko\n", string(b))
+}
+
+func TestJSONFileUpdatePrice(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prices.json")
+
+	assert.NoError(t, JSONFile{Path: path, Append: true}.UpdatePrice(context.Background(), testTradingPairs))
+	assert.NoError(t, JSONFile{Path: path, Append: true}.UpdatePrice(context.Background(), testTradingPairs))
+
+	var records []priceRecord
+	b, _ := ioutil.ReadFile(path)
+	assert.NoError(t, json.Unmarshal(b, &records))
+	assert.Len(t, records, 2)
+	assert.Equal(t, "BTC/USD", records[1].Pair)
+	assert.Equal(t, json.Number("104523.12345678"), records[1].Price)
+
+	assert.NoError(t, JSONFile{Path: path}.UpdatePrice(context.Background(), testTradingPairs))
+
+	records = nil
+	b, _ = ioutil.ReadFile(path)
+	assert.NoError(t, json.Unmarshal(b, &records))
+	assert.Len(t, records, 1)
+
+	matches, _ := filepath.Glob(filepath.Join(filepath.Dir(path), ".*.tmp"))
+	assert.Empty(t, matches)
+}
+
+func TestFileUpdatePriceKeepsStale(t *testing.T) {
+	dir := t.TempDir()
+	fund := TradingPair{BaseSymbol: "SCBNK225", QuoteSymbol: "THB", Price: decimal.RequireFromString("12.3456"), UpdatedTime: time.Date(2021, time.November, 4, 0, 0, 0, 0, time.UTC), Source: "thaisec"}
+	stalePairs := append([]TradingPair{{BaseSymbol: "SCBNK225", Stale: true}}, testTradingPairs...)
+
+	csvPath := filepath.Join(dir, "prices.csv")
+	assert.NoError(t, CSVFile{Path: csvPath}.UpdatePrice(context.Background(), []TradingPair{fund}))
+	assert.NoError(t, CSVFile{Path: csvPath}.UpdatePrice(context.Background(), stalePairs))
+	assert.NoError(t, CSVFile{Path: csvPath}.UpdatePrice(context.Background(), stalePairs))
+
+	b, _ := ioutil.ReadFile(csvPath)
+	assert.Equal(t, "pair,base,quote,price,updated_time,source\n"+
+		"BTC/USD,BTC,USD,104523.12345678,2021-11-05T10:30:00Z,coingecko\n"+
+		"SCBNK225/THB,SCBNK225,THB,12.3456,2021-11-04T00:00:00Z,thaisec (stale)\n", string(b))
+
+	jsonPath := filepath.Join(dir, "prices.json")
+	assert.NoError(t, JSONFile{Path: jsonPath}.UpdatePrice(context.Background(), []TradingPair{fund}))
+	assert.NoError(t, JSONFile{Path: jsonPath}.UpdatePrice(context.Background(), stalePairs))
+
+	var records []priceRecord
+	b, _ = ioutil.ReadFile(jsonPath)
+	assert.NoError(t, json.Unmarshal(b, &records))
+	assert.Len(t, records, 2)
+	assert.Equal(t, "SCBNK225/THB", records[1].Pair)
+	assert.Equal(t, json.Number("12.3456"), records[1].Price)
+	assert.True(t, records[1].Stale)
+	assert.False(t, records[0].Stale)
+}
+
+func TestFileUpdatePriceKeepsStalePair(t *testing.T) {
+	csvPath := filepath.Join(t.TempDir(), "prices.csv")
+	thb := TradingPair{BaseSymbol: "BTC", QuoteSymbol: "THB", Price: decimal.RequireFromString("3400000"), UpdatedTime: time.Date(2021, time.November, 4, 0, 0, 0, 0, time.UTC), Source: "bitkub"}
+	stalePairs := append([]TradingPair{{BaseSymbol: "BTC", QuoteSymbol: "THB", Stale: true}}, testTradingPairs...)
+
+	assert.NoError(t, CSVFile{Path: csvPath}.UpdatePrice(context.Background(), append([]TradingPair{thb}, testTradingPairs...)))
+	assert.NoError(t, CSVFile{Path: csvPath}.UpdatePrice(context.Background(), stalePairs))
+
+	b, _ := ioutil.ReadFile(csvPath)
+	assert.Equal(t, "pair,base,quote,price,updated_time,source\n"+
+		"BTC/USD,BTC,USD,104523.12345678,2021-11-05T10:30:00Z,coingecko\n"+
+		"BTC/THB,BTC,THB,3400000,2021-11-04T00:00:00Z,bitkub (stale)\n", string(b))
+}
+
+func TestWriteFileAtomicKeepsMode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prices.csv")
+	assert.NoError(t, writeFileAtomic(path, []byte("new")))
+	info, _ := os.Stat(path)
+	assert.Equal(t, os.FileMode(0644), info.Mode().Perm())
+
+	assert.NoError(t, os.Chmod(path, 0600))
+	assert.NoError(t, writeFileAtomic(path, []byte("updated")))
+	info, _ = os.Stat(path)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	b, _ := ioutil.ReadFile(path)
+	assert.Equal(t, "updated", string(b))
+}
@@ -0,0 +1,158 @@
+package updater
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"google.golang.org/api/sheets/v4"
+)
+
+type FundDividendUpdater interface {
+	UpdateFundDividends(ctx context.Context, dividends []FundDividend) error
+}
+
+// A Stale FundDividend only carries Fund: the oracle couldn't look its history
+// up this run, so updaters keep the rows last written for it.
+type FundDividend struct {
+	Fund          string
+	XDDate        time.Time
+	PaymentDate   time.Time
+	AmountPerUnit decimal.Decimal
+	Currency      string
+	Source        string
+	Stale         bool
+}
+
+var fundDividendHeaderRow = []string{"Fund", "XD Date", "Payment Date", "Amount Per Unit", "Currency", "Source"}
+
+type fundDividendRecord struct {
+	Fund          string      `json:"fund"`
+	XDDate        string      `json:"xd_date"`
+	PaymentDate   string      `json:"payment_date,omitempty"`
+	AmountPerUnit json.Number `json:"amount_per_unit"`
+	Currency      string      `json:"currency"`
+	Source        string      `json:"source,omitempty"`
+	Stale         bool        `json:"stale,omitempty"`
+}
+
+// UpdateFundDividends replaces the content of DividendSheet with the whole
+// history, one row per payment, so it can be summed next to the NAV.
+func (updater GoogleSheet) UpdateFundDividends(ctx context.Context, dividends []FundDividend) error {
+	svc, err := sheets.NewService(ctx, updater.Option)
+	if err != nil {
+		return err
+	}
+
+	fresh, stale := splitStaleDividends(dividends)
+
+	writeVal := [][]interface{}{stringsRow(fundDividendHeaderRow)}
+	for _, dividend := range fresh {
+		row := stringsRow(fundDividendRow(dividend))
+		row[3] = sheetNumber(dividend.AmountPerUnit)
+		writeVal = append(writeVal, row)
+	}
+
+	if len(stale) > 0 {
+		existing, err := readSheet(ctx, svc, updater.SheetID, updater.DividendSheet)
+		if err != nil {
+			return fmt.Errorf("unable to read existing fund dividends from sheet: %w", err)
+		}
+		writeVal = append(writeVal, staleSheetRows(existing, len(fundDividendHeaderRow), 5, stale)...)
+	}
+
+	if err := replaceSheet(ctx, svc, updater.SheetID, updater.DividendSheet, writeVal); err != nil {
+		return fmt.Errorf("unable to write fund dividends to sheet: %w", err)
+	}
+
+	return nil
+}
+
+func (updater CSVFile) UpdateFundDividends(ctx context.Context, dividends []FundDividend) error {
+	fresh, stale := splitStaleDividends(dividends)
+
+	rows := [][]string{fundDividendHeaderRow}
+	for _, dividend := range fresh {
+		rows = append(rows, fundDividendRow(dividend))
+	}
+
+	if len(stale) > 0 {
+		staleRows, err := readStaleCSVRows(updater.Path, fundDividendHeaderRow, 0, 5, stale)
+		if err != nil {
+			return fmt.Errorf("unable to read existing csv file: %w", err)
+		}
+		rows = append(rows, staleRows...)
+	}
+
+	if err := writeCSVFile(updater.Path, rows); err != nil {
+		return fmt.Errorf("unable to write csv file: %w", err)
+	}
+
+	return nil
+}
+
+func (updater JSONFile) UpdateFundDividends(ctx context.Context, dividends []FundDividend) error {
+	fresh, stale := splitStaleDividends(dividends)
+
+	records := []fundDividendRecord{}
+	for _, dividend := range fresh {
+		records = append(records, fundDividendRecord{
+			Fund:          dividend.Fund,
+			XDDate:        formatFundDate(dividend.XDDate),
+			PaymentDate:   formatFundDate(dividend.PaymentDate),
+			AmountPerUnit: json.Number(dividend.AmountPerUnit.String()),
+			Currency:      dividend.Currency,
+			Source:        dividend.Source,
+		})
+	}
+
+	if len(stale) > 0 {
+		var existing []fundDividendRecord
+		if err := readJSONFile(updater.Path, &existing); err != nil {
+			return fmt.Errorf("unable to read existing json file: %w", err)
+		}
+		for _, record := range existing {
+			if stale[record.Fund] {
+				record.Stale = true
+				records = append(records, record)
+			}
+		}
+	}
+
+	b, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := writeFileAtomic(updater.Path, append(b, '\n')); err != nil {
+		return fmt.Errorf("unable to write json file: %w", err)
+	}
+
+	return nil
+}
+
+func splitStaleDividends(dividends []FundDividend) ([]FundDividend, map[string]bool) {
+	var fresh []FundDividend
+	stale := map[string]bool{}
+	for _, dividend := range dividends {
+		if dividend.Stale {
+			stale[dividend.Fund] = true
+			continue
+		}
+		fresh = append(fresh, dividend)
+	}
+	return fresh, stale
+}
+
+func fundDividendRow(dividend FundDividend) []string {
+	return []string{
+		dividend.Fund,
+		formatFundDate(dividend.XDDate),
+		formatFundDate(dividend.PaymentDate),
+		dividend.AmountPerUnit.String(),
+		dividend.Currency,
+		dividend.Source,
+	}
+}
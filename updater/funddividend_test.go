@@ -0,0 +1,79 @@
+package updater
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+var testFundDividends = []FundDividend{
+	{Fund: "K-USA-A(D)", XDDate: time.Date(2020, time.November, 18, 0, 0, 0, 0, time.UTC), AmountPerUnit: decimal.RequireFromString("0.15"), Currency: "THB", Source: "thaisec"},
+	{Fund: "K-USA-A(D)", XDDate: time.Date(2021, time.May, 20, 0, 0, 0, 0, time.UTC), PaymentDate: time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC), AmountPerUnit: decimal.RequireFromString("0.2500"), Currency: "THB", Source: "thaisec"},
+}
+
+func TestCSVFileUpdateFundDividends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dividends.csv")
+
+	assert.NoError(t, CSVFile{Path: path}.UpdateFundDividends(context.Background(), testFundDividends))
+
+	b, _ := ioutil.ReadFile(path)
+	assert.Equal(t, "Fund,XD Date,Payment Date,Amount Per Unit,Currency,Source\n"+
+		"K-USA-A(D),2020-11-18,,0.15,THB,thaisec\n"+
+		"K-USA-A(D),2021-05-20,2021-06-01,0.25,THB,thaisec\n", string(b))
+}
+
+func TestJSONFileUpdateFundDividends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dividends.json")
+
+	assert.NoError(t, JSONFile{Path: path}.UpdateFundDividends(context.Background(), testFundDividends))
+
+	var records []fundDividendRecord
+	b, _ := ioutil.ReadFile(path)
+	assert.NoError(t, json.Unmarshal(b, &records))
+	assert.Equal(t, []fundDividendRecord{
+		{Fund: "K-USA-A(D)", XDDate: "2020-11-18", AmountPerUnit: "0.15", Currency: "THB", Source: "thaisec"},
+		{Fund: "K-USA-A(D)", XDDate: "2021-05-20", PaymentDate: "2021-06-01", AmountPerUnit: "0.25", Currency: "THB", Source: "thaisec"},
+	}, records)
+
+	assert.NoError(t, JSONFile{Path: path}.UpdateFundDividends(context.Background(), nil))
+
+	b, _ = ioutil.ReadFile(path)
+	assert.Equal(t, "[]\n", string(b))
+}
+
+func TestFileUpdateFundDividendsKeepsStale(t *testing.T) {
+	dir := t.TempDir()
+	kfsdiv := FundDividend{Fund: "KFSDIV", XDDate: time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC), AmountPerUnit: decimal.RequireFromString("0.3"), Currency: "THB", Source: "thaisec"}
+	populated := append([]FundDividend{kfsdiv}, testFundDividends...)
+	staleDividends := []FundDividend{kfsdiv, {Fund: "K-USA-A(D)", Stale: true}}
+
+	csvPath := filepath.Join(dir, "dividends.csv")
+	assert.NoError(t, CSVFile{Path: csvPath}.UpdateFundDividends(context.Background(), populated))
+	assert.NoError(t, CSVFile{Path: csvPath}.UpdateFundDividends(context.Background(), staleDividends))
+	assert.NoError(t, CSVFile{Path: csvPath}.UpdateFundDividends(context.Background(), staleDividends))
+
+	b, _ := ioutil.ReadFile(csvPath)
+	assert.Equal(t, "Fund,XD Date,Payment Date,Amount Per Unit,Currency,Source\n"+
+		"KFSDIV,2021-03-01,,0.3,THB,thaisec\n"+
+		"K-USA-A(D),2020-11-18,,0.15,THB,thaisec (stale)\n"+
+		"K-USA-A(D),2021-05-20,2021-06-01,0.25,THB,thaisec (stale)\n", string(b))
+
+	jsonPath := filepath.Join(dir, "dividends.json")
+	assert.NoError(t, JSONFile{Path: jsonPath}.UpdateFundDividends(context.Background(), populated))
+	assert.NoError(t, JSONFile{Path: jsonPath}.UpdateFundDividends(context.Background(), []FundDividend{{Fund: "KFSDIV", Stale: true}, {Fund: "K-USA-A(D)", Stale: true}}))
+
+	var records []fundDividendRecord
+	b, _ = ioutil.ReadFile(jsonPath)
+	assert.NoError(t, json.Unmarshal(b, &records))
+	assert.Equal(t, []fundDividendRecord{
+		{Fund: "KFSDIV", XDDate: "2021-03-01", AmountPerUnit: "0.3", Currency: "THB", Source: "thaisec", Stale: true},
+		{Fund: "K-USA-A(D)", XDDate: "2020-11-18", AmountPerUnit: "0.15", Currency: "THB", Source: "thaisec", Stale: true},
+		{Fund: "K-USA-A(D)", XDDate: "2021-05-20", PaymentDate: "2021-06-01", AmountPerUnit: "0.25", Currency: "THB", Source: "thaisec", Stale: true},
+	}, records)
+}
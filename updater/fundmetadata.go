@@ -0,0 +1,250 @@
+package updater
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"google.golang.org/api/sheets/v4"
+)
+
+type FundMetadataUpdater interface {
+	UpdateFundMetadata(ctx context.Context, funds []FundMetadata) error
+}
+
+// A Stale FundMetadata only carries Fund: the oracle couldn't look it up this
+// run, so updaters keep the row last written for it.
+type FundMetadata struct {
+	Fund              string
+	Name              string
+	AMC               string
+	RiskSpectrum      string
+	TotalExpenseRatio *decimal.Decimal
+	DividendPolicy    string
+	InceptionDate     time.Time
+	UpdatedTime       time.Time
+	Source            string
+	Stale             bool
+}
+
+var fundMetadataHeaderRow = []string{"Fund", "Name", "AMC", "Risk Spectrum", "Total Expense Ratio %", "Dividend Policy", "Inception Date", "Updated Time", "Source"}
+
+const fundDateFormat = "2006-01-02"
+
+type fundMetadataRecord struct {
+	Fund              string      `json:"fund"`
+	Name              string      `json:"name"`
+	AMC               string      `json:"amc"`
+	RiskSpectrum      string      `json:"risk_spectrum"`
+	TotalExpenseRatio json.Number `json:"total_expense_ratio,omitempty"`
+	DividendPolicy    string      `json:"dividend_policy"`
+	InceptionDate     string      `json:"inception_date,omitempty"`
+	UpdatedTime       time.Time   `json:"updated_time"`
+	Source            string      `json:"source,omitempty"`
+	Stale             bool        `json:"stale,omitempty"`
+}
+
+// UpdateFundMetadata replaces the content of FundInfoSheet, a tab of its own
+// created if missing, so the price range is left alone.
+func (updater GoogleSheet) UpdateFundMetadata(ctx context.Context, funds []FundMetadata) error {
+	svc, err := sheets.NewService(ctx, updater.Option)
+	if err != nil {
+		return err
+	}
+
+	fresh, stale := splitStaleFunds(funds)
+
+	writeVal := [][]interface{}{stringsRow(fundMetadataHeaderRow)}
+	for _, fund := range fresh {
+		row := stringsRow(fundMetadataRow(fund))
+		if fund.TotalExpenseRatio != nil {
+			row[4] = sheetNumber(*fund.TotalExpenseRatio)
+		}
+		writeVal = append(writeVal, row)
+	}
+
+	if len(stale) > 0 {
+		existing, err := readSheet(ctx, svc, updater.SheetID, updater.FundInfoSheet)
+		if err != nil {
+			return fmt.Errorf("unable to read existing fund info from sheet: %w", err)
+		}
+		writeVal = append(writeVal, staleSheetRows(existing, len(fundMetadataHeaderRow), 7, stale)...)
+	}
+
+	if err := replaceSheet(ctx, svc, updater.SheetID, updater.FundInfoSheet, writeVal); err != nil {
+		return fmt.Errorf("unable to write fund info to sheet: %w", err)
+	}
+
+	return nil
+}
+
+func (updater CSVFile) UpdateFundMetadata(ctx context.Context, funds []FundMetadata) error {
+	fresh, stale := splitStaleFunds(funds)
+
+	rows := [][]string{fundMetadataHeaderRow}
+	for _, fund := range fresh {
+		rows = append(rows, fundMetadataRow(fund))
+	}
+
+	if len(stale) > 0 {
+		staleRows, err := readStaleCSVRows(updater.Path, fundMetadataHeaderRow, 0, 8, stale)
+		if err != nil {
+			return fmt.Errorf("unable to read existing csv file: %w", err)
+		}
+		rows = append(rows, staleRows...)
+	}
+
+	if err := writeCSVFile(updater.Path, rows); err != nil {
+		return fmt.Errorf("unable to write csv file: %w", err)
+	}
+
+	return nil
+}
+
+func (updater JSONFile) UpdateFundMetadata(ctx context.Context, funds []FundMetadata) error {
+	fresh, stale := splitStaleFunds(funds)
+
+	records := []fundMetadataRecord{}
+	for _, fund := range fresh {
+		record := fundMetadataRecord{
+			Fund:           fund.Fund,
+			Name:           fund.Name,
+			AMC:            fund.AMC,
+			RiskSpectrum:   fund.RiskSpectrum,
+			DividendPolicy: fund.DividendPolicy,
+			InceptionDate:  formatFundDate(fund.InceptionDate),
+			UpdatedTime:    fund.UpdatedTime,
+			Source:         fund.Source,
+		}
+		if fund.TotalExpenseRatio != nil {
+			record.TotalExpenseRatio = json.Number(fund.TotalExpenseRatio.String())
+		}
+		records = append(records, record)
+	}
+
+	if len(stale) > 0 {
+		var existing []fundMetadataRecord
+		if err := readJSONFile(updater.Path, &existing); err != nil {
+			return fmt.Errorf("unable to read existing json file: %w", err)
+		}
+		for _, record := range existing {
+			if stale[record.Fund] {
+				record.Stale = true
+				records = append(records, record)
+			}
+		}
+	}
+
+	b, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := writeFileAtomic(updater.Path, append(b, '\n')); err != nil {
+		return fmt.Errorf("unable to write json file: %w", err)
+	}
+
+	return nil
+}
+
+func splitStaleFunds(funds []FundMetadata) ([]FundMetadata, map[string]bool) {
+	var fresh []FundMetadata
+	stale := map[string]bool{}
+	for _, fund := range funds {
+		if fund.Stale {
+			stale[fund.Fund] = true
+			continue
+		}
+		fresh = append(fresh, fund)
+	}
+	return fresh, stale
+}
+
+func fundMetadataRow(fund FundMetadata) []string {
+	ratio := ""
+	if fund.TotalExpenseRatio != nil {
+		ratio = fund.TotalExpenseRatio.String()
+	}
+
+	return []string{
+		fund.Fund,
+		fund.Name,
+		fund.AMC,
+		fund.RiskSpectrum,
+		ratio,
+		fund.DividendPolicy,
+		formatFundDate(fund.InceptionDate),
+		fund.UpdatedTime.Format(time.RFC3339),
+		fund.Source,
+	}
+}
+
+func formatFundDate(date time.Time) string {
+	if date.IsZero() {
+		return ""
+	}
+	return date.Format(fundDateFormat)
+}
+
+// replaceSheet clears the tab named title, creating it if missing, and writes
+// rows from its first cell.
+func replaceSheet(ctx context.Context, svc *sheets.Service, sheetID, title string, rows [][]interface{}) error {
+	if _, err := ensureSheetExists(ctx, svc, sheetID, title); err != nil {
+		return err
+	}
+
+	if err := deleteExistingCells(ctx, svc, sheetID, sheetRange(title, "A:Z")); err != nil {
+		return err
+	}
+
+	_, err := svc.Spreadsheets.Values.Update(sheetID, sheetRange(title, "A1"), &sheets.ValueRange{Values: rows}).ValueInputOption("USER_ENTERED").Context(ctx).Do()
+	return err
+}
+
+// readSheet returns the content of the tab named title, creating it if missing.
+func readSheet(ctx context.Context, svc *sheets.Service, sheetID, title string) ([][]interface{}, error) {
+	if _, err := ensureSheetExists(ctx, svc, sheetID, title); err != nil {
+		return nil, err
+	}
+
+	res, err := svc.Spreadsheets.Values.Get(sheetID, sheetRange(title, "A:Z")).
+		ValueRenderOption("FORMULA").
+		DateTimeRenderOption("FORMATTED_STRING").
+		Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	return res.Values, nil
+}
+
+// staleSheetRows returns the existing rows below the header whose first cell
+// is stale, padded to width with their markColumn marked as stale.
+func staleSheetRows(existing [][]interface{}, width, markColumn int, stale map[string]bool) [][]interface{} {
+	var rows [][]interface{}
+	for i, row := range existing {
+		if i == 0 || len(row) == 0 || !stale[fmt.Sprint(row[0])] {
+			continue
+		}
+
+		out := make([]interface{}, width)
+		for j := range out {
+			out[j] = ""
+			if j < len(row) {
+				out[j] = row[j]
+			}
+		}
+		out[markColumn] = markStale(fmt.Sprint(out[markColumn]))
+		rows = append(rows, out)
+	}
+	return rows
+}
+
+func stringsRow(values []string) []interface{} {
+	row := make([]interface{}, len(values))
+	for i, value := range values {
+		row[i] = value
+	}
+	return row
+}
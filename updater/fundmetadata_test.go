@@ -0,0 +1,99 @@
+package updater
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+var testFundMetadata = []FundMetadata{
+	{
+		Fund:              "K-USA-A(A)",
+		Name:              "K US Equity Fund",
+		AMC:               "KASIKORN ASSET MANAGEMENT CO., LTD.",
+		RiskSpectrum:      "6",
+		TotalExpenseRatio: decimalPtr("1.6050"),
+		DividendPolicy:    "ไม่จ่าย",
+		InceptionDate:     time.Date(2017, time.May, 24, 0, 0, 0, 0, time.UTC),
+		UpdatedTime:       time.Date(2021, time.November, 5, 10, 30, 0, 0, time.UTC),
+		Source:            "thaisec",
+	},
+	{Fund: "SCBNK225", UpdatedTime: time.Date(2021, time.November, 5, 10, 30, 0, 0, time.UTC)},
+}
+
+func decimalPtr(value string) *decimal.Decimal {
+	d := decimal.RequireFromString(value)
+	return &d
+}
+
+func TestCSVFileUpdateFundMetadata(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "funds.csv")
+
+	assert.NoError(t, CSVFile{Path: path}.UpdateFundMetadata(context.Background(), testFundMetadata))
+
+	b, _ := ioutil.ReadFile(path)
+	assert.Equal(t, "Fund,Name,AMC,Risk Spectrum,Total Expense Ratio %,Dividend Policy,Inception Date,Updated Time,Source\n"+
+		"K-USA-A(A),K US Equity Fund,\"KASIKORN ASSET MANAGEMENT CO., LTD.\",6,1.605,ไม่จ่าย,2017-05-24,2021-11-05T10:30:00Z,thaisec\n"+
+		"SCBNK225,,,,,,,2021-11-05T10:30:00Z,\n", string(b))
+}
+
+func TestJSONFileUpdateFundMetadata(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "funds.json")
+
+	assert.NoError(t, JSONFile{Path: path}.UpdateFundMetadata(context.Background(), testFundMetadata))
+
+	var records []fundMetadataRecord
+	b, _ := ioutil.ReadFile(path)
+	assert.NoError(t, json.Unmarshal(b, &records))
+	assert.Len(t, records, 2)
+	assert.Equal(t, json.Number("1.605"), records[0].TotalExpenseRatio)
+	assert.Equal(t, "2017-05-24", records[0].InceptionDate)
+	assert.NotContains(t, string(b), `"total_expense_ratio": ""`)
+	assert.Equal(t, json.Number(""), records[1].TotalExpenseRatio)
+}
+
+func TestFileUpdateFundMetadataKeepsStale(t *testing.T) {
+	dir := t.TempDir()
+	staleFunds := []FundMetadata{{Fund: "K-USA-A(A)", Stale: true}, testFundMetadata[1]}
+
+	csvPath := filepath.Join(dir, "funds.csv")
+	assert.NoError(t, CSVFile{Path: csvPath}.UpdateFundMetadata(context.Background(), testFundMetadata))
+	assert.NoError(t, CSVFile{Path: csvPath}.UpdateFundMetadata(context.Background(), staleFunds))
+	assert.NoError(t, CSVFile{Path: csvPath}.UpdateFundMetadata(context.Background(), staleFunds))
+
+	b, _ := ioutil.ReadFile(csvPath)
+	assert.Equal(t, "Fund,Name,AMC,Risk Spectrum,Total Expense Ratio %,Dividend Policy,Inception Date,Updated Time,Source\n"+
+		"SCBNK225,,,,,,,2021-11-05T10:30:00Z,\n"+
+		"K-USA-A(A),K US Equity Fund,\"KASIKORN ASSET MANAGEMENT CO., LTD.\",6,1.605,ไม่จ่าย,2017-05-24,2021-11-05T10:30:00Z,thaisec (stale)\n", string(b))
+
+	jsonPath := filepath.Join(dir, "funds.json")
+	assert.NoError(t, JSONFile{Path: jsonPath}.UpdateFundMetadata(context.Background(), testFundMetadata))
+	assert.NoError(t, JSONFile{Path: jsonPath}.UpdateFundMetadata(context.Background(), staleFunds))
+
+	var records []fundMetadataRecord
+	b, _ = ioutil.ReadFile(jsonPath)
+	assert.NoError(t, json.Unmarshal(b, &records))
+	assert.Len(t, records, 2)
+	assert.False(t, records[0].Stale)
+	assert.Equal(t, "K-USA-A(A)", records[1].Fund)
+	assert.Equal(t, json.Number("1.605"), records[1].TotalExpenseRatio)
+	assert.True(t, records[1].Stale)
+}
+
+func TestStaleSheetRows(t *testing.T) {
+	existing := [][]interface{}{
+		{"Fund", "Name"},
+		{"KFSDIV", "KF Dividend", "5"},
+		{"SCBNK225", "SCB Nikkei 225"},
+		{},
+	}
+
+	rows := staleSheetRows(existing, 3, 2, map[string]bool{"SCBNK225": true})
+	assert.Equal(t, [][]interface{}{{"SCBNK225", "SCB Nikkei 225", " (stale)"}}, rows)
+}
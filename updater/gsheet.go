@@ -8,8 +8,10 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
+	"github.com/shopspring/decimal"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 	"google.golang.org/api/option"
@@ -17,11 +19,20 @@ import (
 )
 
 var headerRow = []interface{}{"Pair", "Price", "Updated Time"}
+var marketHeaderRow = []interface{}{"Bid", "Ask", "24h Change %", "24h Volume", "Market Cap", "24h High", "24h Low"}
+var historyHeaderRow = []interface{}{"Timestamp", "Pair", "Price", "Source"}
+
+const historyTimeFormat = "2006-01-02 15:04:05"
 
 type GoogleSheet struct {
-	Option     option.ClientOption
-	SheetID    string
-	WriteRange string
+	Option        option.ClientOption
+	SheetID       string
+	WriteRange    string
+	Merge         bool
+	HistorySheet  string
+	MarketColumns bool
+	FundInfoSheet string
+	DividendSheet string
 }
 
 func NewGoogleSheet(serviceAccountTokenPath, sheetID, writeRange string) *GoogleSheet {
@@ -60,31 +71,228 @@ func (updater GoogleSheet) UpdatePrice(ctx context.Context, tradingPairs []Tradi
 		return err
 	}
 
-	if err := deleteExistingCells(svc, updater.SheetID, updater.WriteRange); err != nil {
+	fresh, stale := splitStale(tradingPairs)
+
+	var existing [][]interface{}
+	if updater.Merge || len(stale) > 0 {
+		res, err := svc.Spreadsheets.Values.Get(updater.SheetID, updater.WriteRange).
+			ValueRenderOption("FORMULA").
+			DateTimeRenderOption("FORMATTED_STRING").
+			Context(ctx).Do()
+		if err != nil {
+			return fmt.Errorf("unable to read existing data from sheet: %w", err)
+		}
+		existing = res.Values
+	}
+
+	var writeVal [][]interface{}
+	if updater.Merge {
+		writeVal = updater.mergeRows(existing, fresh)
+	} else {
+		if err := deleteExistingCells(ctx, svc, updater.SheetID, updater.WriteRange); err != nil {
+			return err
+		}
+
+		writeVal = append(writeVal, updater.headerRow())
+		for _, pair := range fresh {
+			writeVal = append(writeVal, updater.tradingPairRow(pair))
+		}
+	}
+	writeVal = updater.keepStaleRows(writeVal, existing, stale)
+
+	_, err = svc.Spreadsheets.Values.Update(updater.SheetID, updater.WriteRange, &sheets.ValueRange{Values: writeVal}).ValueInputOption("USER_ENTERED").Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("unable to write data to sheet: %w", err)
+	}
+
+	if updater.HistorySheet != "" {
+		if err := updater.appendHistory(ctx, svc, fresh); err != nil {
+			return fmt.Errorf("unable to append price history: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (updater GoogleSheet) appendHistory(ctx context.Context, svc *sheets.Service, tradingPairs []TradingPair) error {
+	created, err := ensureSheetExists(ctx, svc, updater.SheetID, updater.HistorySheet)
+	if err != nil {
 		return err
 	}
 
-	writeVal := [][]interface{}{}
-	writeVal = append(writeVal, headerRow)
+	var writeVal [][]interface{}
+	if created {
+		writeVal = append(writeVal, historyHeaderRow)
+	}
+	writeVal = append(writeVal, historyRows(tradingPairs)...)
+
+	_, err = svc.Spreadsheets.Values.Append(updater.SheetID, sheetRange(updater.HistorySheet, "A:D"), &sheets.ValueRange{Values: writeVal}).
+		ValueInputOption("USER_ENTERED").
+		InsertDataOption("INSERT_ROWS").
+		Context(ctx).Do()
+
+	return err
+}
 
+func historyRows(tradingPairs []TradingPair) [][]interface{} {
+	var rows [][]interface{}
 	for _, pair := range tradingPairs {
-		writeVal = append(writeVal, []interface{}{
-			fmt.Sprintf("%s/%s", pair.BaseSymbol, pair.QuoteSymbol),
-			pair.Price,
-			pair.UpdatedTime.Local().Format(time.RFC1123),
+		rows = append(rows, []interface{}{
+			pair.UpdatedTime.Local().Format(historyTimeFormat),
+			pairName(pair),
+			sheetNumber(pair.Price),
+			pair.Source,
 		})
 	}
+	return rows
+}
 
-	_, err = svc.Spreadsheets.Values.Update(updater.SheetID, updater.WriteRange, &sheets.ValueRange{Values: writeVal}).ValueInputOption("USER_ENTERED").Do()
+func ensureSheetExists(ctx context.Context, svc *sheets.Service, sheetID, title string) (bool, error) {
+	spreadsheet, err := svc.Spreadsheets.Get(sheetID).Fields("sheets.properties.title").Context(ctx).Do()
 	if err != nil {
-		return fmt.Errorf("unable to write data to sheet: %w", err)
+		return false, err
 	}
 
-	return nil
+	for _, sheet := range spreadsheet.Sheets {
+		if sheet.Properties.Title == title {
+			return false, nil
+		}
+	}
+
+	_, err = svc.Spreadsheets.BatchUpdate(sheetID, &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{
+			{AddSheet: &sheets.AddSheetRequest{Properties: &sheets.SheetProperties{Title: title}}},
+		},
+	}).Context(ctx).Do()
+	if err != nil {
+		return false, fmt.Errorf("unable to create sheet %s: %w", title, err)
+	}
+
+	return true, nil
+}
+
+func sheetRange(title, cells string) string {
+	return fmt.Sprintf("'%s'!%s", strings.ReplaceAll(title, "'", "''"), cells)
+}
+
+func (updater GoogleSheet) headerRow() []interface{} {
+	if !updater.MarketColumns {
+		return headerRow
+	}
+	return append(append([]interface{}{}, headerRow...), marketHeaderRow...)
+}
+
+func (updater GoogleSheet) tradingPairRow(pair TradingPair) []interface{} {
+	row := []interface{}{
+		pairName(pair),
+		sheetNumber(pair.Price),
+		pair.UpdatedTime.Local().Format(time.RFC1123),
+	}
+	if !updater.MarketColumns {
+		return row
+	}
+
+	for _, value := range []*decimal.Decimal{pair.Bid, pair.Ask, pair.ChangePercent24h, pair.Volume24h, pair.MarketCap, pair.High24h, pair.Low24h} {
+		if value == nil {
+			row = append(row, "")
+			continue
+		}
+		row = append(row, sheetNumber(*value))
+	}
+	return row
+}
+
+// sheetNumber keeps every digit of a decimal while still sending it to the
+// Sheets API as a JSON number rather than a string.
+func sheetNumber(value decimal.Decimal) json.Number {
+	return json.Number(value.String())
+}
+
+func pairName(pair TradingPair) string {
+	return fmt.Sprintf("%s/%s", pair.BaseSymbol, pair.QuoteSymbol)
+}
+
+// mergeRows keeps existing rows in place, rewrites the managed columns of rows
+// whose Pair matches and appends rows for new pairs.
+func (updater GoogleSheet) mergeRows(existing [][]interface{}, tradingPairs []TradingPair) [][]interface{} {
+	header := updater.headerRow()
+	out := [][]interface{}{header}
+	rowIndex := map[string]int{}
+
+	for i, row := range existing {
+		if i == 0 {
+			continue
+		}
+		if len(row) > len(header) {
+			row = row[:len(header)]
+		}
+		if len(row) > 0 {
+			rowIndex[fmt.Sprint(row[0])] = len(out)
+		}
+		out = append(out, row)
+	}
+
+	for _, pair := range tradingPairs {
+		if i, ok := rowIndex[pairName(pair)]; ok {
+			out[i] = updater.tradingPairRow(pair)
+			continue
+		}
+		rowIndex[pairName(pair)] = len(out)
+		out = append(out, updater.tradingPairRow(pair))
+	}
+
+	return out
+}
+
+// keepStaleRows carries over the existing rows of pairs the oracle couldn't
+// quote, with their Updated Time marked as stale.
+func (updater GoogleSheet) keepStaleRows(rows, existing [][]interface{}, stale staleSet) [][]interface{} {
+	if len(stale) == 0 {
+		return rows
+	}
+
+	rowIndex := map[string]int{}
+	for i, row := range rows {
+		if i > 0 && len(row) > 0 {
+			rowIndex[fmt.Sprint(row[0])] = i
+		}
+	}
+
+	width := len(updater.headerRow())
+	for i, row := range existing {
+		if i == 0 || len(row) == 0 {
+			continue
+		}
+		name := fmt.Sprint(row[0])
+		if !stale.has(name) {
+			continue
+		}
+
+		if j, ok := rowIndex[name]; ok {
+			rows[j] = markStaleRow(rows[j])
+			continue
+		}
+		if len(row) > width {
+			row = row[:width]
+		}
+		rowIndex[name] = len(rows)
+		rows = append(rows, markStaleRow(row))
+	}
+
+	return rows
+}
+
+func markStaleRow(row []interface{}) []interface{} {
+	out := append([]interface{}{}, row...)
+	for len(out) < len(headerRow) {
+		out = append(out, "")
+	}
+	out[2] = markStale(fmt.Sprint(out[2]))
+	return out
 }
 
-func deleteExistingCells(svc *sheets.Service, sheetID, clearRange string) error {
-	if _, err := svc.Spreadsheets.Values.Clear(sheetID, clearRange, &sheets.ClearValuesRequest{}).Do(); err != nil {
+func deleteExistingCells(ctx context.Context, svc *sheets.Service, sheetID, clearRange string) error {
+	if _, err := svc.Spreadsheets.Values.Clear(sheetID, clearRange, &sheets.ClearValuesRequest{}).Context(ctx).Do(); err != nil {
 		return err
 	}
 	return nil
@@ -0,0 +1,141 @@
+package updater
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/shopspring/decimal"
+)
+
+const LedgerFormat = "ledger"
+const BeancountFormat = "beancount"
+
+const priceDBDateFormat = "2006-01-02"
+
+var beancountInvalidCommodityChars = regexp.MustCompile(`[^A-Z0-9'._-]+`)
+
+type PriceDB struct {
+	Path   string
+	Format string
+}
+
+func (updater PriceDB) UpdatePrice(ctx context.Context, tradingPairs []TradingPair) error {
+	if updater.Format != LedgerFormat && updater.Format != BeancountFormat {
+		return fmt.Errorf("unsupported price db format %s", updater.Format)
+	}
+
+	existing, err := ioutil.ReadFile(updater.Path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("unable to read price db file: %w", err)
+	}
+
+	seen := map[string]bool{}
+	for _, line := range strings.Split(string(existing), "\n") {
+		if key, ok := parsePriceDirectiveKey(line); ok {
+			seen[key] = true
+		}
+	}
+
+	var out strings.Builder
+	if len(existing) > 0 && existing[len(existing)-1] != '\n' {
+		out.WriteString("\n")
+	}
+	fresh, _ := splitStale(tradingPairs)
+	for _, pair := range fresh {
+		line := updater.formatPriceDirective(pair)
+		key, _ := parsePriceDirectiveKey(line)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out.WriteString(line + "\n")
+	}
+
+	f, err := os.OpenFile(updater.Path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("unable to open price db file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(out.String()); err != nil {
+		return fmt.Errorf("unable to append to price db file: %w", err)
+	}
+
+	return f.Sync()
+}
+
+func (updater PriceDB) formatPriceDirective(pair TradingPair) string {
+	date := pair.UpdatedTime.Format(priceDBDateFormat)
+	price := pair.Price.String()
+
+	if updater.Format == BeancountFormat {
+		return fmt.Sprintf("%s price %s %s %s", date, beancountCommodity(pair.BaseSymbol), price, beancountCommodity(pair.QuoteSymbol))
+	}
+	return fmt.Sprintf("P %s %s %s %s", date, ledgerCommodity(pair.BaseSymbol), price, ledgerCommodity(pair.QuoteSymbol))
+}
+
+// parsePriceDirectiveKey returns date+commodity+quote commodity of a Ledger "P"
+// or Beancount "price" directive, which is what directives are deduplicated on.
+func parsePriceDirectiveKey(line string) (string, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 4 {
+		return "", false
+	}
+
+	switch {
+	case fields[0] == "P":
+		commodityIndex := 2
+		if strings.Contains(fields[2], ":") && len(fields) > 4 {
+			commodityIndex = 3
+		}
+		return fields[1] + " " + strings.Trim(fields[commodityIndex], `"`) + " " + quoteCommodity(fields[commodityIndex+1:]), true
+	case fields[1] == "price":
+		return fields[0] + " " + fields[2] + " " + quoteCommodity(fields[3:]), true
+	}
+
+	return "", false
+}
+
+// quoteCommodity picks the commodity out of a directive amount such as
+// "61000.5 USD", "$61000" or "12.3456 \"THB\"".
+func quoteCommodity(amount []string) string {
+	for _, token := range amount {
+		token = strings.Trim(token, `"`)
+		if token == "" {
+			continue
+		}
+		if _, err := decimal.NewFromString(strings.ReplaceAll(token, ",", "")); err == nil {
+			continue
+		}
+		if r := []rune(token)[0]; unicode.IsLetter(r) {
+			return token
+		}
+		return strings.TrimRightFunc(token, func(r rune) bool {
+			return unicode.IsDigit(r) || r == '.' || r == ','
+		})
+	}
+	return ""
+}
+
+func ledgerCommodity(symbol string) string {
+	for _, r := range symbol {
+		if !unicode.IsLetter(r) {
+			return strconv.Quote(symbol)
+		}
+	}
+	return symbol
+}
+
+func beancountCommodity(symbol string) string {
+	commodity := beancountInvalidCommodityChars.ReplaceAllString(strings.ToUpper(symbol), "-")
+	return strings.TrimRightFunc(commodity, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
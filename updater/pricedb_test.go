@@ -0,0 +1,89 @@
+package updater
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriceDBUpdatePrice(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prices.ledger")
+	updatedTime := time.Date(2021, time.November, 5, 12, 0, 0, 0, time.Local)
+	pairs := []TradingPair{
+		{BaseSymbol: "BTC", QuoteSymbol: "USD", Price: decimal.RequireFromString("61000.5"), UpdatedTime: updatedTime},
+		{BaseSymbol: "SCBNK225", QuoteSymbol: "THB", Price: decimal.RequireFromString("12.3456"), UpdatedTime: updatedTime},
+	}
+
+	assert.NoError(t, ioutil.WriteFile(path, []byte("P 2021-11-05 BTC 60000 USD"), 0644))
+	assert.NoError(t, PriceDB{Path: path, Format: LedgerFormat}.UpdatePrice(context.Background(), pairs))
+	assert.NoError(t, PriceDB{Path: path, Format: LedgerFormat}.UpdatePrice(context.Background(), pairs))
+
+	b, _ := ioutil.ReadFile(path)
+	assert.Equal(t, "P 2021-11-05 BTC 60000 USD\nP 2021-11-05 \"SCBNK225\" 12.3456 THB\n", string(b))
+}
+
+func TestPriceDBUpdatePriceSeveralQuotes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prices.beancount")
+	updatedTime := time.Date(2021, time.November, 5, 12, 0, 0, 0, time.Local)
+	pairs := []TradingPair{
+		{BaseSymbol: "BTC", QuoteSymbol: "USD", Price: decimal.RequireFromString("61000.5"), UpdatedTime: updatedTime},
+		{BaseSymbol: "BTC", QuoteSymbol: "THB", Price: decimal.RequireFromString("2012345"), UpdatedTime: updatedTime},
+	}
+
+	assert.NoError(t, PriceDB{Path: path, Format: BeancountFormat}.UpdatePrice(context.Background(), pairs))
+	assert.NoError(t, PriceDB{Path: path, Format: BeancountFormat}.UpdatePrice(context.Background(), pairs))
+
+	b, _ := ioutil.ReadFile(path)
+	assert.Equal(t, "2021-11-05 price BTC 61000.5 USD\n2021-11-05 price BTC 2012345 THB\n", string(b))
+}
+
+func TestParseLedgerPriceDirectiveKey(t *testing.T) {
+	for line, expected := range map[string]string{
+		"P 2021-11-05 BTC 61000.5 USD":            "2021-11-05 BTC USD",
+		"P 2021-11-05 12:00:00 BTC 2,012,345 THB": "2021-11-05 BTC THB",
+		"P 2021-11-05 BTC $61000":                 "2021-11-05 BTC $",
+		`P 2021-11-05 "SCBNK225" 12.3456 THB`:     "2021-11-05 SCBNK225 THB",
+		`P 2021-11-05 BTC 2 "SCBNK225"`:           "2021-11-05 BTC SCBNK225",
+	} {
+		key, ok := parsePriceDirectiveKey(line)
+		assert.True(t, ok)
+		assert.Equal(t, expected, key)
+	}
+}
+
+func TestFormatBeancountPriceDirective(t *testing.T) {
+	updater := PriceDB{Format: BeancountFormat}
+	line := updater.formatPriceDirective(TradingPair{
+		BaseSymbol:  "K-USA-A(A)",
+		QuoteSymbol: "THB",
+		Price:       decimal.RequireFromString("15.25"),
+		UpdatedTime: time.Date(2021, time.November, 5, 12, 0, 0, 0, time.Local),
+	})
+
+	assert.Equal(t, "2021-11-05 price K-USA-A-A 15.25 THB", line)
+
+	key, ok := parsePriceDirectiveKey(line)
+	assert.True(t, ok)
+	assert.Equal(t, "2021-11-05 K-USA-A-A THB", key)
+}
+
+func TestFormatPriceDirectiveKeepsQuoteDate(t *testing.T) {
+	local := time.Local
+	time.Local = time.UTC
+	t.Cleanup(func() { time.Local = local })
+
+	bkk, _ := time.LoadLocation("Asia/Bangkok")
+	line := PriceDB{Format: LedgerFormat}.formatPriceDirective(TradingPair{
+		BaseSymbol:  "SCBNK225",
+		QuoteSymbol: "THB",
+		Price:       decimal.RequireFromString("12.3456"),
+		UpdatedTime: time.Date(2021, time.November, 5, 0, 0, 0, 0, bkk),
+	})
+
+	assert.Equal(t, `P 2021-11-05 "SCBNK225" 12.3456 THB`, line)
+}
@@ -0,0 +1,152 @@
+package updater
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/lib/pq"
+	_ "modernc.org/sqlite"
+)
+
+const SQLiteDriver = "sqlite"
+const PostgresDriver = "postgres"
+
+const sqliteTimeFormat = "2006-01-02T15:04:05Z"
+
+// priceColumnTypes keeps every digit of a price: SQLite keeps a NUMERIC column
+// as a 64-bit float, so it stores prices as TEXT.
+var priceColumnTypes = map[string]string{
+	SQLiteDriver:   "TEXT",
+	PostgresDriver: "NUMERIC",
+}
+
+func migrations(driver string) []string {
+	return []string{
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS prices (
+		pair TEXT NOT NULL,
+		base TEXT NOT NULL,
+		quote TEXT NOT NULL,
+		price %s NOT NULL,
+		source TEXT NOT NULL DEFAULT '',
+		observed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+		PRIMARY KEY (pair, observed_at)
+	)`, priceColumnTypes[driver]),
+		`CREATE INDEX IF NOT EXISTS prices_observed_at_idx ON prices (observed_at)`,
+	}
+}
+
+const upsertPriceQuery = `INSERT INTO prices (pair, base, quote, price, source, observed_at)
+	VALUES (?, ?, ?, ?, ?, ?)
+	ON CONFLICT (pair, observed_at) DO UPDATE SET price = excluded.price, source = excluded.source`
+
+type SQLDatabase struct {
+	Driver string
+	DSN    string
+}
+
+func (updater SQLDatabase) UpdatePrice(ctx context.Context, tradingPairs []TradingPair) error {
+	if updater.Driver != SQLiteDriver && updater.Driver != PostgresDriver {
+		return fmt.Errorf("unsupported sql driver %s", updater.Driver)
+	}
+
+	db, err := sql.Open(updater.Driver, updater.DSN)
+	if err != nil {
+		return fmt.Errorf("unable to open database: %w", err)
+	}
+	defer db.Close()
+
+	if err := migrate(ctx, db, updater.Driver); err != nil {
+		return fmt.Errorf("unable to migrate database: %w", err)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, rebind(updater.Driver, upsertPriceQuery))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	fresh, _ := splitStale(tradingPairs)
+	for _, pair := range fresh {
+		_, err := stmt.ExecContext(ctx,
+			pairName(pair),
+			pair.BaseSymbol,
+			pair.QuoteSymbol,
+			pair.Price.String(),
+			pair.Source,
+			observedAt(updater.Driver, pair.UpdatedTime),
+		)
+		if err != nil {
+			return fmt.Errorf("unable to upsert price of %s: %w", pairName(pair), err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func migrate(ctx context.Context, db *sql.DB, driver string) error {
+	if _, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY)`); err != nil {
+		return err
+	}
+
+	var version int
+	if err := db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version); err != nil {
+		return err
+	}
+
+	driverMigrations := migrations(driver)
+
+	for i := version; i < len(driverMigrations); i++ {
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, driverMigrations[i]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("version=%d %w", i+1, err)
+		}
+		if _, err := tx.ExecContext(ctx, rebind(driver, `INSERT INTO schema_migrations (version) VALUES (?)`), i+1); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func rebind(driver, query string) string {
+	if driver != PostgresDriver {
+		return query
+	}
+
+	var out strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			out.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		out.WriteRune(r)
+	}
+	return out.String()
+}
+
+func observedAt(driver string, t time.Time) interface{} {
+	if driver == SQLiteDriver {
+		return t.UTC().Format(sqliteTimeFormat)
+	}
+	return t.UTC()
+}
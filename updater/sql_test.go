@@ -0,0 +1,42 @@
+package updater
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLDatabaseUpdatePrice(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "prices.db")
+	updater := SQLDatabase{Driver: SQLiteDriver, DSN: dsn}
+
+	assert.NoError(t, updater.UpdatePrice(context.Background(), testTradingPairs))
+	assert.NoError(t, updater.UpdatePrice(context.Background(), testTradingPairs))
+
+	db, err := sql.Open(SQLiteDriver, dsn)
+	if err != nil {
+		t.FailNow()
+	}
+	defer db.Close()
+
+	var count int
+	assert.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM prices WHERE pair = 'BTC/USD'`).Scan(&count))
+	assert.Equal(t, 1, count)
+
+	var price, observedAt string
+	assert.NoError(t, db.QueryRow(`SELECT price, observed_at FROM prices WHERE pair = 'BTC/USD'`).Scan(&price, &observedAt))
+	assert.Equal(t, "104523.12345678", price)
+	assert.Equal(t, "2021-11-05T10:30:00Z", observedAt)
+
+	var storage string
+	assert.NoError(t, db.QueryRow(`SELECT typeof(price) FROM prices WHERE pair = 'BTC/USD'`).Scan(&storage))
+	assert.Equal(t, "text", storage)
+}
+
+func TestRebind(t *testing.T) {
+	assert.Equal(t, "VALUES ($1, $2)", rebind(PostgresDriver, "VALUES (?, ?)"))
+	assert.Equal(t, "VALUES (?, ?)", rebind(SQLiteDriver, "VALUES (?, ?)"))
+}
@@ -2,16 +2,67 @@ package updater
 
 import (
 	"context"
+	"strings"
 	"time"
+
+	"github.com/shopspring/decimal"
 )
 
 type Updater interface {
 	UpdatePrice(ctx context.Context, tradingPairs []TradingPair) error
 }
 
+// A Stale pair only carries BaseSymbol, and QuoteSymbol when a single quote
+// currency is missing: the oracle couldn't quote it this run, so updaters keep
+// the values last written for it, in any quote currency without QuoteSymbol.
 type TradingPair struct {
 	BaseSymbol  string
 	QuoteSymbol string
-	Price       float32
+	Price       decimal.Decimal
 	UpdatedTime time.Time
+	Source      string
+	Stale       bool
+
+	Bid              *decimal.Decimal
+	Ask              *decimal.Decimal
+	ChangePercent24h *decimal.Decimal
+	Volume24h        *decimal.Decimal
+	MarketCap        *decimal.Decimal
+	High24h          *decimal.Decimal
+	Low24h           *decimal.Decimal
+}
+
+const staleSuffix = " (stale)"
+
+// splitStale separates the pairs to write from the base symbols, or BASE/QUOTE
+// pairs, the oracle couldn't quote this run.
+func splitStale(tradingPairs []TradingPair) ([]TradingPair, staleSet) {
+	var fresh []TradingPair
+	stale := staleSet{}
+	for _, pair := range tradingPairs {
+		if pair.Stale {
+			if pair.QuoteSymbol != "" {
+				stale[pairName(pair)] = true
+			} else {
+				stale[pair.BaseSymbol] = true
+			}
+			continue
+		}
+		fresh = append(fresh, pair)
+	}
+	return fresh, stale
+}
+
+type staleSet map[string]bool
+
+// has tells whether the pair named BASE/QUOTE is stale.
+func (stale staleSet) has(name string) bool {
+	return stale[name] || stale[strings.SplitN(name, "/", 2)[0]]
+}
+
+func markStale(value string) string {
+	if strings.HasSuffix(value, staleSuffix) {
+		return value
+	}
+	return value + staleSuffix
 }
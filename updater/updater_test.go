@@ -1,8 +1,11 @@
 package updater
 
 import (
+	"encoding/json"
 	"testing"
+	"time"
 
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/api/option"
 )
@@ -18,3 +21,95 @@ func TestNewGoogleSheet(t *testing.T) {
 		NewGoogleSheet("/test", "test", "A:B"),
 	)
 }
+
+func TestMergeRows(t *testing.T) {
+	updatedTime := time.Unix(1636000000, 0)
+	existing := [][]interface{}{
+		{"Pair", "Price", "Updated Time", "Note"},
+		{"ETH/USD", "4000", "old", "my note"},
+		{},
+		{"BTC/USD", "=A1*2", "old"},
+	}
+
+	rows := GoogleSheet{}.mergeRows(existing, []TradingPair{
+		{BaseSymbol: "ADA", QuoteSymbol: "USD", Price: decimal.NewFromInt(2), UpdatedTime: updatedTime},
+		{BaseSymbol: "ETH", QuoteSymbol: "USD", Price: decimal.NewFromInt(4500), UpdatedTime: updatedTime},
+	})
+
+	assert.Equal(t, [][]interface{}{
+		headerRow,
+		{"ETH/USD", json.Number("4500"), updatedTime.Local().Format(time.RFC1123)},
+		{},
+		{"BTC/USD", "=A1*2", "old"},
+		{"ADA/USD", json.Number("2"), updatedTime.Local().Format(time.RFC1123)},
+	}, rows)
+}
+
+func TestTradingPairRowMarketColumns(t *testing.T) {
+	updatedTime := time.Unix(1636000000, 0)
+	bid, ask, change := decimal.NewFromInt(60000), decimal.NewFromInt(60010), decimal.RequireFromString("-1.5")
+	sheet := GoogleSheet{MarketColumns: true}
+
+	assert.Equal(t, []interface{}{
+		"Pair", "Price", "Updated Time", "Bid", "Ask", "24h Change %", "24h Volume", "Market Cap", "24h High", "24h Low",
+	}, sheet.headerRow())
+	assert.Equal(t, []interface{}{
+		"BTC/USD", json.Number("60005"), updatedTime.Local().Format(time.RFC1123), json.Number("60000"), json.Number("60010"), json.Number("-1.5"), "", "", "", "",
+	}, sheet.tradingPairRow(TradingPair{BaseSymbol: "BTC", QuoteSymbol: "USD", Price: decimal.NewFromInt(60005), UpdatedTime: updatedTime, Bid: &bid, Ask: &ask, ChangePercent24h: &change}))
+	assert.Len(t, headerRow, 3)
+}
+
+func TestKeepStaleRows(t *testing.T) {
+	sheet := GoogleSheet{}
+	existing := [][]interface{}{
+		{"Pair", "Price", "Updated Time"},
+		{"SCBNK225/THB", 12.3, "Thu, 04 Nov 2021 00:00:00 +07"},
+		{"SCBNK225/USD", 0.37, "Thu, 04 Nov 2021 00:00:00 +07 (stale)"},
+		{"KFSDIV/THB", 15.1, "Thu, 04 Nov 2021 00:00:00 +07"},
+	}
+	rows := [][]interface{}{
+		headerRow,
+		{"KFSDIV/THB", json.Number("15.2"), "Fri, 05 Nov 2021 00:00:00 +07"},
+	}
+
+	assert.Equal(t, [][]interface{}{
+		headerRow,
+		{"KFSDIV/THB", json.Number("15.2"), "Fri, 05 Nov 2021 00:00:00 +07"},
+		{"SCBNK225/THB", 12.3, "Thu, 04 Nov 2021 00:00:00 +07 (stale)"},
+		{"SCBNK225/USD", 0.37, "Thu, 04 Nov 2021 00:00:00 +07 (stale)"},
+	}, sheet.keepStaleRows(rows, existing, map[string]bool{"SCBNK225": true}))
+
+	merged := sheet.mergeRows(existing, nil)
+	assert.Equal(t, "Thu, 04 Nov 2021 00:00:00 +07 (stale)", sheet.keepStaleRows(merged, existing, map[string]bool{"SCBNK225": true})[1][2])
+}
+
+func TestSplitStale(t *testing.T) {
+	fresh, stale := splitStale([]TradingPair{
+		{BaseSymbol: "KFSDIV", QuoteSymbol: "THB"},
+		{BaseSymbol: "SCBNK225", Stale: true},
+		{BaseSymbol: "BTC", QuoteSymbol: "THB", Stale: true},
+	})
+
+	assert.Equal(t, []TradingPair{{BaseSymbol: "KFSDIV", QuoteSymbol: "THB"}}, fresh)
+	assert.Equal(t, staleSet{"SCBNK225": true, "BTC/THB": true}, stale)
+	assert.True(t, stale.has("SCBNK225/USD"))
+	assert.True(t, stale.has("BTC/THB"))
+	assert.False(t, stale.has("BTC/USD"))
+}
+
+func TestHistoryRows(t *testing.T) {
+	updatedTime := time.Date(2021, time.November, 5, 10, 30, 0, 0, time.Local)
+
+	rows := historyRows([]TradingPair{
+		{BaseSymbol: "BTC", QuoteSymbol: "USD", Price: decimal.NewFromInt(61000), UpdatedTime: updatedTime, Source: "coingecko"},
+	})
+
+	assert.Equal(t, [][]interface{}{
+		{"2021-11-05 10:30:00", "BTC/USD", json.Number("61000"), "coingecko"},
+	}, rows)
+}
+
+func TestSheetRange(t *testing.T) {
+	assert.Equal(t, "'Price History'!A:D", sheetRange("Price History", "A:D"))
+	assert.Equal(t, "'Bob''s'!A1", sheetRange("Bob's", "A1"))
+}